@@ -58,20 +58,23 @@ export class Person {
         this.friends = this.convertValues(source["friends"], Person);
     }
 
-	convertValues(a: any, classs: any, asMap: boolean = false): any {
-	    if (!a) {
+	convertValues(a: any, classs: any, asMap: boolean = false, ...typeArgs: any[]): any {
+	    if (!a || !classs) {
 	        return a;
 	    }
 	    if (a.slice) {
-	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	        return (a as any[]).map(elem => this.convertValues(elem, classs, false, ...typeArgs));
 	    } else if ("object" === typeof a) {
 	        if (asMap) {
 	            for (const key of Object.keys(a)) {
-	                a[key] = new classs(a[key]);
+	                a[key] = this.convertValues(a[key], classs, false, ...typeArgs);
 	            }
 	            return a;
 	        }
-	        return new classs(a);
+	        if (classs.variants) {
+	            return this.convertValues(a, classs.variants[a[classs.discriminator]], false, ...typeArgs);
+	        }
+	        return new classs(a, ...typeArgs);
 	    }
 	    return a;
 	}
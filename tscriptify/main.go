@@ -4,9 +4,13 @@ import (
 	"flag"
 	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/importer"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io/ioutil"
+	"math"
 	"os"
 	"os/exec"
 	"strings"
@@ -14,6 +18,9 @@ import (
 	"time"
 )
 
+// maxSafeInteger is the largest integer JavaScript numbers represent exactly (`Number.MAX_SAFE_INTEGER`).
+const maxSafeInteger = 1<<53 - 1
+
 type arrayImports []string
 
 func (i *arrayImports) String() string {
@@ -37,43 +44,80 @@ import (
 func main() {
 	t := typescriptify.New()
 	t.CreateInterface = {{ .Interface }}
+	t.CreateZodSchema = {{ .Zod }}
+	t.CreateTypeGuards = {{ .Guards }}
+	t.CreateDirIndex = {{ .Index }}
 {{ range $key, $value := .InitParams }}	t.{{ $key }}={{ $value }}
 {{ end }}
 {{ range .Structs }}	t.Add({{ . }}{})
+{{ end }}{{ range .Enums }}	t.AddEnum([]struct {
+		Value  m.{{ .Type }}
+		TSName string
+	}{
+{{ range .Members }}		{ {{ .Value }}, "{{ .Name }}" },
+{{ end }}	})
+{{ end }}{{ range .Consts }}	t.AddConst("{{ . }}", m.{{ . }})
 {{ end }}
 {{ range .CustomImports }}	t.AddImport("{{ . }}")
 {{ end }}
-	err := t.ConvertToFile("{{ .TargetFile }}")
+{{ if .TargetDir }}	err := t.ConvertToDir("{{ .TargetDir }}")
+{{ else }}	err := t.ConvertToFile("{{ .TargetFile }}")
+{{ end }}	if err != nil {
+		panic(err.Error())
+	}
+	for _, warning := range t.Warnings() {
+		fmt.Println("WARNING:", warning)
+	}
+{{ if .JSONSchemaFile }}	err = t.ConvertJSONSchemaToFile("{{ .JSONSchemaFile }}")
 	if err != nil {
 		panic(err.Error())
 	}
-	fmt.Println("OK")
+{{ end }}	fmt.Println("OK")
 }`
 
 type Params struct {
-	ModelsPackage string
-	TargetFile    string
-	Structs       []string
-	InitParams    map[string]interface{}
-	CustomImports arrayImports
-	Interface     bool
-	Verbose       bool
+	ModelsPackage  string
+	TargetFile     string
+	TargetDir      string
+	JSONSchemaFile string
+	Structs        []string
+	Consts         []string
+	Enums          []Enum
+	InitParams     map[string]interface{}
+	CustomImports  arrayImports
+	Interface      bool
+	Zod            bool
+	Guards         bool
+	Index          bool
+	ExportConsts   bool
+	FindEnums      bool
+	Verbose        bool
 }
 
 func main() {
 	var p Params
-	var backupDir string
+	var backupDir, constsModule string
 	flag.StringVar(&p.ModelsPackage, "package", "", "Path of the package with models")
 	flag.StringVar(&p.TargetFile, "target", "", "Target typescript file")
+	flag.StringVar(&p.TargetDir, "targetdir", "", "Target directory (one typescript file for every package)")
+	flag.BoolVar(&p.Index, "index", false, "Create an index.ts file in the target directory")
+	flag.StringVar(&p.JSONSchemaFile, "jsonschema", "", "Target JSON Schema file (optional)")
 	flag.StringVar(&backupDir, "backup", "", "Directory where backup files are saved")
 	flag.BoolVar(&p.Interface, "interface", false, "Create interfaces (not classes)")
+	flag.BoolVar(&p.Zod, "zod", false, "Create zod schemas (not classes)")
+	flag.BoolVar(&p.Guards, "guards", false, "Create type guard functions")
+	flag.BoolVar(&p.ExportConsts, "consts", false, "Export the exported constants of the parsed .go files")
+	flag.StringVar(&constsModule, "constsmodule", "", "Module of constants without a converted type in the target directory (consts.ts by default)")
+	flag.BoolVar(&p.FindEnums, "enums", false, "Convert typed const blocks of the parsed .go files to enums")
 	flag.Var(&p.CustomImports, "import", "Typescript import for your custom type, repeat this option for each import needed")
 	flag.BoolVar(&p.Verbose, "verbose", false, "Verbose logs")
 	flag.Parse()
 
 	structs := []string{}
+	goFiles := []string{}
 	for _, structOrGoFile := range flag.Args() {
 		if strings.HasSuffix(structOrGoFile, ".go") {
+			goFiles = append(goFiles, structOrGoFile)
 			fmt.Println("Parsing:", structOrGoFile)
 			fileStructs, err := GetGolangFileStructs(structOrGoFile)
 			if err != nil {
@@ -84,13 +128,31 @@ func main() {
 			structs = append(structs, structOrGoFile)
 		}
 	}
+	if p.ExportConsts && len(goFiles) > 0 {
+		consts, warnings, err := GetGolangConsts(goFiles)
+		if err != nil {
+			panic(fmt.Sprintf("Error loading/parsing golang files: %s", err.Error()))
+		}
+		for _, warning := range warnings {
+			fmt.Println("WARNING:", warning)
+		}
+		p.Consts = consts
+	}
+	if p.FindEnums && len(goFiles) > 0 {
+		enums, err := GetGolangEnums(goFiles)
+		if err != nil {
+			panic(fmt.Sprintf("Error loading/parsing golang files: %s", err.Error()))
+		}
+		p.Enums = enums
+		p.Consts = withoutEnumMembers(p.Consts, enums)
+	}
 
 	if len(p.ModelsPackage) == 0 {
 		fmt.Fprintln(os.Stderr, "No package given")
 		os.Exit(1)
 	}
-	if len(p.TargetFile) == 0 {
-		fmt.Fprintln(os.Stderr, "No target file")
+	if len(p.TargetFile) == 0 && len(p.TargetDir) == 0 {
+		fmt.Fprintln(os.Stderr, "No target file or directory")
 		os.Exit(1)
 	}
 
@@ -117,6 +179,9 @@ func main() {
 	p.InitParams = map[string]interface{}{
 		"BackupDir": fmt.Sprintf(`"%s"`, backupDir),
 	}
+	if len(constsModule) > 0 {
+		p.InitParams["ConstsModule"] = fmt.Sprintf(`"%s"`, constsModule)
+	}
 	err = t.Execute(f, p)
 	handleErr(err)
 
@@ -150,6 +215,185 @@ func GetGolangFileStructs(filename string) ([]string, error) {
 	return v.structs, nil
 }
 
+// GetGolangConsts returns the names of the exported top-level constants in the files (from the same package), and
+// warnings for the constants which are skipped: complex values, untyped constants overflowing their default type (like
+// `math.MaxUint64`, which can't be passed to `AddConst()`) and integers JavaScript numbers can't represent exactly.
+func GetGolangConsts(filenames []string) ([]string, []string, error) {
+	files, info, _, err := checkPackage(filenames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var consts, warnings []string
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			genDecl, is := decl.(*ast.GenDecl)
+			if !is || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					if !name.IsExported() {
+						continue
+					}
+					if c, is := info.Defs[name].(*types.Const); is {
+						if problem := constProblem(c); problem != "" {
+							warnings = append(warnings, fmt.Sprintf("const %s skipped: %s", name.Name, problem))
+							continue
+						}
+					}
+					consts = append(consts, name.Name)
+				}
+			}
+		}
+	}
+
+	return consts, warnings, nil
+}
+
+// constProblem returns why the value of a constant can't be converted to a TypeScript literal, or "". Constants of
+// types which failed the type check are kept, their values are unknown.
+func constProblem(c *types.Const) string {
+	basic, is := c.Type().Underlying().(*types.Basic)
+	if !is || basic.Kind() == types.Invalid || c.Val().Kind() == constant.Unknown {
+		return ""
+	}
+	if basic.Info()&types.IsComplex != 0 {
+		return "complex values can't be converted to TypeScript literals"
+	}
+	if basic.Info()&types.IsUntyped != 0 {
+		// The constant is passed to AddConst() with its default type:
+		switch defaultType := types.Default(basic).(*types.Basic); defaultType.Kind() {
+		case types.Int, types.Int32:
+			min, max := int64(math.MinInt), int64(math.MaxInt)
+			if defaultType.Kind() == types.Int32 {
+				min, max = math.MinInt32, math.MaxInt32
+			}
+			if constant.Compare(c.Val(), token.LSS, constant.MakeInt64(min)) || constant.Compare(c.Val(), token.GTR, constant.MakeInt64(max)) {
+				return fmt.Sprintf("%s overflows %s", c.Val().ExactString(), defaultType.Name())
+			}
+		case types.Float64:
+			if f, _ := constant.Float64Val(c.Val()); math.IsInf(f, 0) {
+				return fmt.Sprintf("%s overflows float64", c.Val().String())
+			}
+		}
+	}
+	if basic.Info()&types.IsInteger != 0 {
+		if val := constant.ToInt(c.Val()); constant.Compare(val, token.LSS, constant.MakeInt64(-maxSafeInteger)) || constant.Compare(val, token.GTR, constant.MakeInt64(maxSafeInteger)) {
+			return fmt.Sprintf("%s can't be represented exactly by a JavaScript number", c.Val().ExactString())
+		}
+	}
+	return ""
+}
+
+// Enum is a named type with its constants, converted with AddEnum().
+type Enum struct {
+	Type    string
+	Members []EnumMember
+}
+
+// EnumMember is an enum constant, Value is the Go expression of its value.
+type EnumMember struct {
+	Name  string
+	Value string
+}
+
+// GetGolangEnums returns the named types (declared in the files) with at least two constants of that type, the
+// files must be from the same package. Constants are evaluated with go/types, so iota and constant expressions can be
+// used.
+func GetGolangEnums(filenames []string) ([]Enum, error) {
+	files, info, pkg, err := checkPackage(filenames)
+	if err != nil {
+		return nil, err
+	}
+
+	var enums []Enum
+	indexes := map[*types.TypeName]int{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			genDecl, is := decl.(*ast.GenDecl)
+			if !is || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					c, is := info.Defs[name].(*types.Const)
+					if !is || name.Name == "_" {
+						continue
+					}
+					named, is := c.Type().(*types.Named)
+					if !is || named.Obj().Pkg() != pkg || !named.Obj().Exported() {
+						continue
+					}
+					basic, is := named.Underlying().(*types.Basic)
+					if !is || basic.Info()&(types.IsInteger|types.IsString) == 0 {
+						continue
+					}
+					n, found := indexes[named.Obj()]
+					if !found {
+						n = len(enums)
+						indexes[named.Obj()] = n
+						enums = append(enums, Enum{Type: named.Obj().Name()})
+					}
+					value := "m." + name.Name
+					if !name.IsExported() {
+						value = fmt.Sprintf("m.%s(%s)", named.Obj().Name(), c.Val().ExactString())
+					}
+					enums[n].Members = append(enums[n].Members, EnumMember{Name: name.Name, Value: value})
+				}
+			}
+		}
+	}
+
+	var result []Enum
+	for _, enum := range enums {
+		// A single typed constant isn't an enum:
+		if len(enum.Members) > 1 {
+			result = append(result, enum)
+		}
+	}
+	return result, nil
+}
+
+// checkPackage parses the files (from the same package) and type-checks them with go/types.
+func checkPackage(filenames []string) ([]*ast.File, *types.Info, *types.Package, error) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, filename := range filenames {
+		f, err := parser.ParseFile(fset, filename, nil, 0)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		files = append(files, f)
+	}
+
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	conf := types.Config{
+		Importer: importer.Default(),
+		// Imported packages can be missing, constants can be evaluated anyway:
+		Error: func(err error) {},
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, info)
+	return files, info, pkg, nil
+}
+
+// withoutEnumMembers removes the constants which are converted as enum members.
+func withoutEnumMembers(consts []string, enums []Enum) []string {
+	members := map[string]bool{}
+	for _, enum := range enums {
+		for _, member := range enum.Members {
+			members[member.Name] = true
+		}
+	}
+	var result []string
+	for _, c := range consts {
+		if !members[c] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
 type AVisitor struct {
 	structNameCandidate string
 	structs             []string
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGolangFileConsts(t *testing.T) {
+	t.Parallel()
+
+	consts, warnings, err := GetGolangConsts([]string{"testdata/models.go"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Sunday", "Monday", "Red", "Green", "DefaultLevel", "MaxPageSize", "Timeout", "APIKeyHeader", "Answer", "Question"}, consts)
+	assert.Empty(t, warnings)
+}
+
+func TestGetGolangConstsSkipsUnconvertibleValues(t *testing.T) {
+	t.Parallel()
+
+	// The files are type-checked together, Pages uses MaxPageSize from models.go:
+	consts, warnings, err := GetGolangConsts([]string{"testdata/models.go", "testdata/limits.go"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Sunday", "Monday", "Red", "Green", "DefaultLevel", "MaxPageSize", "Timeout", "APIKeyHeader", "Answer", "Question", "MaxSafe", "LargeFloat", "Letter", "Unsigned", "Pages"}, consts)
+	assert.Equal(t, []string{
+		"const MaxUint skipped: 18446744073709551615 overflows int",
+		"const TooBig skipped: 9223372036854775808 overflows int",
+		"const TooPrecise skipped: 1152921504606846976 can't be represented exactly by a JavaScript number",
+		"const Huge skipped: 1e+400 overflows float64",
+		"const Imaginary skipped: complex values can't be converted to TypeScript literals",
+		"const TypedMaxUint skipped: 18446744073709551615 can't be represented exactly by a JavaScript number",
+	}, warnings)
+}
+
+func TestGetGolangEnums(t *testing.T) {
+	t.Parallel()
+
+	enums, err := GetGolangEnums([]string{"testdata/models.go"})
+	assert.Nil(t, err)
+	assert.Equal(t, []Enum{
+		{Type: "Weekday", Members: []EnumMember{
+			{Name: "Sunday", Value: "m.Sunday"},
+			{Name: "Monday", Value: "m.Monday"},
+			{Name: "tuesday", Value: "m.Weekday(3)"},
+		}},
+		{Type: "Color", Members: []EnumMember{
+			{Name: "Red", Value: "m.Red"},
+			{Name: "Green", Value: "m.Green"},
+		}},
+	}, enums)
+
+	consts, _, err := GetGolangConsts([]string{"testdata/models.go"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"DefaultLevel", "MaxPageSize", "Timeout", "APIKeyHeader", "Answer", "Question"}, withoutEnumMembers(consts, enums))
+}
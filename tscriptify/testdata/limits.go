@@ -0,0 +1,18 @@
+package models
+
+import "math"
+
+// Constants which can't be converted to TypeScript literals are skipped.
+const (
+	MaxUint             = math.MaxUint64
+	TooBig              = 1 << 63
+	TooPrecise          = 1 << 60
+	Huge                = 1e400
+	Imaginary           = 2i
+	MaxSafe             = 1<<53 - 1
+	LargeFloat          = 1e300
+	Letter              = 'a'
+	Unsigned     uint64 = 1 << 40
+	TypedMaxUint uint64 = math.MaxUint64
+	Pages               = MaxPageSize * 2
+)
@@ -0,0 +1,34 @@
+// Package models is used to test the discovery of constants and enums.
+package models
+
+import "time"
+
+type Weekday int
+
+const (
+	_ Weekday = iota
+	Sunday
+	Monday
+	tuesday
+)
+
+type Color string
+
+const (
+	Red   Color = "red"
+	Green Color = "green"
+)
+
+// Level has only one constant, so it isn't an enum.
+type Level int
+
+const DefaultLevel Level = 3
+
+const (
+	MaxPageSize      = 100
+	Timeout          = 5 * time.Second
+	APIKeyHeader     = "X-API-Key"
+	internalLimit    = 10
+	_                = 0
+	Answer, Question = 42, "?"
+)
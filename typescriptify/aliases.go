@@ -0,0 +1,114 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AliasMode defines how named primitive types (`type UserID string`) are converted.
+type AliasMode int
+
+const (
+	// NoTypeAliases converts named primitive types to their underlying type (default).
+	NoTypeAliases AliasMode = iota
+	// TypeAliases declares `type UserID = string` and uses `UserID` for the fields of that type.
+	TypeAliases
+	// BrandedTypeAliases declares `type UserID = string & { readonly __brand: "UserID" }`, so values of different
+	// aliases (or plain strings) can't be mixed up.
+	BrandedTypeAliases
+)
+
+// WithTypeAliases sets if named primitive types are converted to (branded) type aliases.
+func (t *TypeScriptify) WithTypeAliases(mode AliasMode) *TypeScriptify {
+	t.Aliases = mode
+	return t
+}
+
+// isPrimitiveAlias checks if a type is converted to a type alias: a named bool, number or string type which isn't an
+// enum and has no custom JSON encoding. Managed types are aliases of their TypeScript type.
+func (t *TypeScriptify) isPrimitiveAlias(typ reflect.Type) bool {
+	if t.Aliases == NoTypeAliases || typ.Name() == "" || typ.PkgPath() == "" {
+		return false
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return false
+	}
+	if _, isEnum := t.enums[typ]; isEnum {
+		return false
+	}
+	ptr := reflect.PtrTo(typ)
+	return !ptr.Implements(jsonMarshalerType) && !ptr.Implements(textMarshalerType)
+}
+
+// isAliasField checks if a field (or pointer) is typed with its alias, fields with their own `ts_*` options (or the
+// `,string` option) keep them.
+func (t *TypeScriptify) isAliasField(typ reflect.Type, opts TypeOptions) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if !t.isPrimitiveAlias(typ) {
+		return false
+	}
+	typeOpts, _ := t.typeOptions(typ)
+	return opts.TSType == typeOpts.TSType && opts.TSTransform == typeOpts.TSTransform && opts.TSZod == typeOpts.TSZod
+}
+
+// aliasTarget returns the TypeScript type a primitive alias stands for.
+func (t *TypeScriptify) aliasTarget(typ reflect.Type) string {
+	if opts, found := t.typeOptions(typ); found && opts.TSType != "" {
+		return opts.TSType
+	}
+	return t.kinds[typ.Kind()]
+}
+
+// convertAlias returns the type alias declaration (with its schema or type guard), or "" if already converted.
+func (t *TypeScriptify) convertAlias(depth int, typ reflect.Type) string {
+	if _, found := t.alreadyConverted[typ]; found {
+		return ""
+	}
+	t.logf(depth, "Converting alias %s", typ.String())
+	t.alreadyConverted[typ] = true
+	t.beginDeclaration()
+
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+	entityName := t.typeName(typ)
+	target := t.aliasTarget(typ)
+
+	var result string
+	if t.CreateZodSchema {
+		schemaName := zodSchemaName(entityName)
+		t.zodDeclared[schemaName] = true
+		opts, _ := t.typeOptions(typ)
+		if opts.TSZod == "" && opts.TSTransform == "" && opts.TSType == "" {
+			opts.TSZod = t.zodKindSchema(typ.Kind())
+		}
+		schema := t.zodFieldSchema(nil, fieldInfo{types: []reflect.Type{typ}, opts: opts})
+		if t.Aliases == BrandedTypeAliases {
+			schema += fmt.Sprintf(".brand<%q>()", entityName)
+		}
+		result = fmt.Sprintf("%sconst %s = %s;\n%stype %s = z.infer<typeof %s>;", export, schemaName, schema, export, entityName, schemaName)
+	} else {
+		if t.Aliases == BrandedTypeAliases {
+			target = fmt.Sprintf("%s & { readonly __brand: %q }", target, entityName)
+		}
+		result = fmt.Sprintf("%stype %s = %s;", export, entityName, target)
+		if t.CreateTypeGuards {
+			condition := "true"
+			switch tsType := t.aliasTarget(typ); tsType {
+			case "string", "number", "boolean", "bigint":
+				condition = fmt.Sprintf(`"%s" === typeof x`, tsType)
+			}
+			result += fmt.Sprintf("\n%sfunction %s(x: unknown): x is %s {\n%sreturn %s;\n}", export, guardName(entityName), entityName, t.Indent, condition)
+		}
+	}
+
+	return t.declare(typ, result) + "\n"
+}
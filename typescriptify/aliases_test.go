@@ -0,0 +1,221 @@
+package typescriptify
+
+import (
+	"testing"
+)
+
+type UserID string
+
+type Cents int64
+
+type Account struct {
+	ID      UserID            `json:"id"`
+	Owner   *UserID           `json:"owner"`
+	Members []UserID          `json:"members"`
+	Balance Cents             `json:"balance"`
+	Limits  map[string]Cents  `json:"limits"`
+	Quoted  Cents             `json:"quoted,string"`
+	Custom  UserID            `json:"custom" ts_type:"string"`
+	Tags    map[UserID]string `json:"tags"`
+}
+
+func TestTypeAliases(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Account{}).WithTypeAliases(TypeAliases).WithPrefix("API").WithBackupDir("")
+	desiredResult := `export type APICents = number;
+export type APIUserID = string;
+export class APIAccount {
+    id: APIUserID;
+    owner?: APIUserID;
+    members: APIUserID[];
+    balance: APICents;
+    limits: {[key: string]: APICents};
+    quoted: string;
+    custom: string;
+    tags: {[key: string]: string};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.id = source["id"];
+        this.owner = source["owner"];
+        this.members = source["members"];
+        this.balance = source["balance"];
+        this.limits = source["limits"];
+        this.quoted = source["quoted"];
+        this.custom = source["custom"];
+        this.tags = source["tags"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new APIAccount({"id": "u1"}).id === "u1"`,
+	})
+
+	// Without the option, the types are erased:
+	converter = New().Add(Account{}).WithBackupDir("")
+	desiredResult = `export class Account {
+    id: string;
+    owner?: string;
+    members: string[];
+    balance: number;
+    limits: {[key: string]: number};
+    quoted: string;
+    custom: string;
+    tags: {[key: string]: string};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.id = source["id"];
+        this.owner = source["owner"];
+        this.members = source["members"];
+        this.balance = source["balance"];
+        this.limits = source["limits"];
+        this.quoted = source["quoted"];
+        this.custom = source["custom"];
+        this.tags = source["tags"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestBrandedTypeAliases(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Account{}).WithTypeAliases(BrandedTypeAliases).WithInterface(true).WithBackupDir("")
+	desiredResult := `export type Cents = number & { readonly __brand: "Cents" };
+export type UserID = string & { readonly __brand: "UserID" };
+export interface Account {
+    id: UserID;
+    owner?: UserID;
+    members: UserID[];
+    balance: Cents;
+    limits: {[key: string]: Cents};
+    quoted: string;
+    custom: string;
+    tags: {[key: string]: string};
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`((a: Account) => a.id === "u1")({id: "u1" as UserID} as Account)`,
+	})
+}
+
+func TestTypeAliasesWithManagedTypes(t *testing.T) {
+	t.Parallel()
+
+	// Decimal isn't declared, so the code isn't compiled:
+	converter := New().
+		Add(Account{}).
+		WithTypeAliases(TypeAliases).
+		ManageType(Cents(0), TypeOptions{TSType: "Decimal", TSTransform: "new Decimal(__VALUE__)"}).
+		WithBackupDir("")
+	desiredResult := `export type Cents = Decimal;
+export type UserID = string;
+export class Account {
+    id: UserID;
+    owner?: UserID;
+    members: UserID[];
+    balance: Cents;
+    limits: {[key: string]: Cents};
+    quoted: Cents;
+    custom: string;
+    tags: {[key: string]: string};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.id = source["id"];
+        this.owner = source["owner"];
+        this.members = source["members"];
+        this.balance = new Decimal(source["balance"]);
+        this.limits = ((v1: any) => { if (v1 == null) return v1; for (const k1 of Object.keys(v1)) v1[k1] = new Decimal(v1[k1]); return v1; })(source["limits"]);
+        this.quoted = new Decimal(source["quoted"]);
+        this.custom = source["custom"];
+        this.tags = source["tags"];
+    }
+}`
+	testConvertedCode(t, converter, desiredResult)
+
+	// BigInt() needs the ES2020 lib, so the code isn't compiled here:
+	type Wallet struct {
+		Balance Cents `json:"balance,string"`
+	}
+	converter = New().Add(Wallet{}).WithTypeAliases(TypeAliases).WithInt64Mode(Int64AsBigInt).WithBackupDir("")
+	desiredResult = `export type Cents = bigint;
+export class Wallet {
+    balance: Cents;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.balance = source["balance"] == null ? source["balance"] : BigInt(source["balance"]);
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "balance": this.balance == null ? this.balance : this.balance.toString(),
+        };
+    }
+}`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestTypeAliasesZodAndGuards(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Account{}).WithTypeAliases(BrandedTypeAliases).WithZodSchema(true).WithBackupDir("")
+	desiredResult := `import { z } from "zod";
+
+export const CentsSchema = z.number().brand<"Cents">();
+export type Cents = z.infer<typeof CentsSchema>;
+export const UserIDSchema = z.string().brand<"UserID">();
+export type UserID = z.infer<typeof UserIDSchema>;
+export const AccountSchema = z.object({
+    id: UserIDSchema,
+    owner: UserIDSchema.optional(),
+    members: z.array(UserIDSchema),
+    balance: CentsSchema,
+    limits: z.record(z.string(), CentsSchema),
+    quoted: z.string(),
+    custom: z.custom<string>(),
+    tags: z.record(z.string(), z.string()),
+});
+export type Account = z.infer<typeof AccountSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	converter = New().Add(Account{}).WithTypeAliases(TypeAliases).WithTypeGuards(true).WithInterface(true).WithBackupDir("")
+	desiredResult = `export type Cents = number;
+export function isCents(x: unknown): x is Cents {
+    return "number" === typeof x;
+}
+export type UserID = string;
+export function isUserID(x: unknown): x is UserID {
+    return "string" === typeof x;
+}
+export interface Account {
+    id: UserID;
+    owner?: UserID;
+    members: UserID[];
+    balance: Cents;
+    limits: {[key: string]: Cents};
+    quoted: string;
+    custom: string;
+    tags: {[key: string]: string};
+}
+export function isAccount(x: unknown): x is Account {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return isUserID(o["id"])
+        && (o["owner"] === undefined || o["owner"] === null || isUserID(o["owner"]))
+        && (o["members"] === null || Array.isArray(o["members"]) && o["members"].every((v1: unknown) => isUserID(v1)))
+        && isCents(o["balance"])
+        && (o["limits"] === null || "object" === typeof o["limits"] && o["limits"] !== null && !Array.isArray(o["limits"]) && Object.values(o["limits"]).every((v1: unknown) => isCents(v1)))
+        && "string" === typeof o["quoted"]
+        && o["custom"] !== undefined
+        && (o["tags"] === null || "object" === typeof o["tags"] && o["tags"] !== null && !Array.isArray(o["tags"]) && Object.values(o["tags"]).every((v1: unknown) => "string" === typeof v1));
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isAccount({"id": "u1", "owner": "u2", "members": [], "balance": 1, "limits": {}, "quoted": "1", "custom": "", "tags": {}})`,
+		`!isAccount({"id": 1, "owner": "u2", "members": [], "balance": 1, "limits": {}, "quoted": "1", "custom": "", "tags": {}})`,
+	})
+}
@@ -0,0 +1,69 @@
+package typescriptify
+
+import (
+	"reflect"
+	"strings"
+)
+
+// WithInlineAnonymous converts anonymous structs into object literal types (`meta: { page: number }`) instead of
+// classes/interfaces named after the parent struct and field (`Response_Meta`).
+func (t *TypeScriptify) WithInlineAnonymous(b bool) *TypeScriptify {
+	t.InlineAnonymous = b
+	return t
+}
+
+// isInlineStruct checks if the type is an anonymous struct converted to an object literal type.
+func (t *TypeScriptify) isInlineStruct(typ reflect.Type) bool {
+	return t.InlineAnonymous && typ.Kind() == reflect.Struct && typ.Name() == ""
+}
+
+// hasInlineStruct checks if the type is (or is a pointer, slice, array or map of) an inline anonymous struct.
+func (t *TypeScriptify) hasInlineStruct(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	return t.isInlineStruct(typ)
+}
+
+// structFields returns the fields of a (non generic) struct, with the same JSON names and options as in
+// `convertType()`.
+func (t *TypeScriptify) structFields(typ reflect.Type) []fieldInfo {
+	var result []fieldInfo
+	for _, field := range deepFields(typ) {
+		fieldType := field.Type
+		isPtr := field.Type.Kind() == reflect.Ptr
+		if isPtr {
+			field.Type = field.Type.Elem()
+		}
+		jsonFieldName := t.getJSONFieldName(field, isPtr)
+		if len(jsonFieldName) == 0 {
+			continue
+		}
+		fldOpts := t.getFieldOptions(typ, field)
+		result = append(result, fieldInfo{name: jsonFieldName, doc: fldOpts.TSDoc, types: []reflect.Type{fieldType}, opts: fldOpts, nullable: t.isNullable(field, isPtr), canBeNull: t.canBeNull(field, isPtr), quoted: t.quotedField(field)})
+	}
+	return result
+}
+
+// inlineStructType returns the object literal type for an anonymous struct.
+func (t *TypeScriptify) inlineStructType(typ reflect.Type) string {
+	var fields []string
+	for _, fld := range t.structFields(typ) {
+		fieldType := fld.opts.TSType
+		if fieldType == "" || t.isAliasField(fld.types[0], fld.opts) {
+			fieldType = t.typeExpr(fld.types[0])
+		}
+		name, optional := fld.name, ""
+		if strings.HasSuffix(name, "?") {
+			name, optional = strings.TrimSuffix(name, "?"), "?"
+		}
+		if fld.nullable {
+			fieldType += " | null"
+		}
+		fields = append(fields, tsPropertyName(name)+optional+": "+fieldType)
+	}
+	if len(fields) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(fields, "; ") + " }"
+}
@@ -0,0 +1,295 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Response struct {
+	Meta struct {
+		Page   int `json:"page"`
+		Paging *struct {
+			Next string `json:"next"`
+		} `json:"paging"`
+	} `json:"meta"`
+	Items []struct {
+		Address Address `json:"address"`
+	} `json:"items"`
+	ByKey map[string]struct {
+		Address *Address `json:"address"`
+		Count   int      `json:"count"`
+	} `json:"by_key"`
+}
+
+func TestAnonymousStructsHoisted(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Response{}).
+		WithBackupDir("")
+
+	desiredResult := `export class Response_ByKey {
+    address?: Address;
+    count: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.address = this.convertValues(source["address"], Address);
+        this.count = source["count"];
+    }
+
+	` + tsConvertValuesFunc + `
+}
+export class Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.duration = source["duration"];
+        this.text = source["text"];
+        this.Text2 = source["Text2"];
+    }
+}
+export class Response_Items {
+    address: Address;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.address = this.convertValues(source["address"], Address);
+    }
+
+	` + tsConvertValuesFunc + `
+}
+export class Response_Meta_Paging {
+    next: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.next = source["next"];
+    }
+}
+export class Response_Meta {
+    page: number;
+    paging?: Response_Meta_Paging;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.page = source["page"];
+        this.paging = this.convertValues(source["paging"], Response_Meta_Paging);
+    }
+
+	` + tsConvertValuesFunc + `
+}
+export class Response {
+    meta: Response_Meta;
+    items: Response_Items[];
+    by_key: {[key: string]: Response_ByKey};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.meta = this.convertValues(source["meta"], Response_Meta);
+        this.items = this.convertValues(source["items"], Response_Items);
+        this.by_key = this.convertValues(source["by_key"], Response_ByKey, true);
+    }
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := `{"meta": {"page": 2, "paging": {"next": "n"}}, "items": [{"address": {"duration": 1}}], "by_key": {"a": {"address": {"duration": 2}, "count": 3}}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Response(` + jsn + `).meta.paging instanceof Response_Meta_Paging`,
+		`new Response(` + jsn + `).items[0].address instanceof Address`,
+		`new Response(` + jsn + `).by_key["a"].address instanceof Address`,
+	})
+}
+
+func TestAnonymousStructsInline(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Response{}).
+		WithInlineAnonymous(true).
+		WithBackupDir("")
+
+	desiredResult := `export class Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.duration = source["duration"];
+        this.text = source["text"];
+        this.Text2 = source["Text2"];
+    }
+}
+export class Response {
+    meta: { page: number; paging?: { next: string } };
+    items: { address: Address }[];
+    by_key: {[key: string]: { address?: Address; count: number }};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.meta = source["meta"];
+        this.items = ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => ((v2: any) => v2 == null ? v2 : {...v2, "address": this.convertValues(v2["address"], Address)})(e1)))(source["items"]);
+        this.by_key = ((v1: any) => { if (v1 == null) return v1; for (const k1 of Object.keys(v1)) v1[k1] = ((v2: any) => v2 == null ? v2 : {...v2, "address": this.convertValues(v2["address"], Address)})(v1[k1]); return v1; })(source["by_key"]);
+    }
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := `{"meta": {"page": 2, "paging": {"next": "n"}}, "items": [{"address": {"duration": 1}}], "by_key": {"a": {"address": {"duration": 2}, "count": 3}}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Response(` + jsn + `).meta.paging?.next === "n"`,
+		`new Response(` + jsn + `).items[0].address instanceof Address`,
+		`new Response(` + jsn + `).by_key["a"].address instanceof Address`,
+		`new Response(` + jsn + `).by_key["a"].count === 3`,
+		`new Response({"meta": {"page": 1}, "items": null, "by_key": null}).items === null`,
+	})
+}
+
+func TestAnonymousStructsInlineGuardsAndSchemas(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Response{}).
+		WithInlineAnonymous(true).
+		WithInterface(true).
+		WithTypeGuards(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+}
+export function isAddress(x: unknown): x is Address {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "number" === typeof o["duration"]
+        && (o["text"] === undefined || "string" === typeof o["text"])
+        && (o["Text2"] === undefined || "string" === typeof o["Text2"]);
+}
+export interface Response {
+    meta: { page: number; paging?: { next: string } };
+    items: { address: Address }[];
+    by_key: {[key: string]: { address?: Address; count: number }};
+}
+export function isResponse(x: unknown): x is Response {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return ((o1: any) => "object" === typeof o1 && o1 !== null && "number" === typeof o1["page"] && (o1["paging"] === undefined || o1["paging"] === null || ((o2: any) => "object" === typeof o2 && o2 !== null && "string" === typeof o2["next"])(o1["paging"])))(o["meta"])
+        && (o["items"] === null || Array.isArray(o["items"]) && o["items"].every((v1: unknown) => ((o2: any) => "object" === typeof o2 && o2 !== null && isAddress(o2["address"]))(v1)))
+        && (o["by_key"] === null || "object" === typeof o["by_key"] && o["by_key"] !== null && !Array.isArray(o["by_key"]) && Object.values(o["by_key"]).every((v1: unknown) => ((o2: any) => "object" === typeof o2 && o2 !== null && (o2["address"] === undefined || o2["address"] === null || isAddress(o2["address"])) && "number" === typeof o2["count"])(v1)));
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isResponse({"meta": {"page": 2}, "items": [{"address": {"duration": 1}}], "by_key": {}})`,
+		`!isResponse({"meta": {"page": "2"}, "items": [], "by_key": {}})`,
+		`!isResponse({"meta": {"page": 2}, "items": [{"address": {}}], "by_key": {}})`,
+	})
+
+	converter = New().Add(Response{}).WithInlineAnonymous(true).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export const AddressSchema = z.object({
+    duration: z.number(),
+    text: z.string().optional(),
+    Text2: z.string().optional(),
+});
+export type Address = z.infer<typeof AddressSchema>;
+export const ResponseSchema = z.object({
+    meta: z.object({ page: z.number(), paging: z.object({ next: z.string() }).optional() }),
+    items: z.array(z.object({ address: AddressSchema })),
+    by_key: z.record(z.string(), z.object({ address: AddressSchema.optional(), count: z.number() })),
+});
+export type Response = z.infer<typeof ResponseSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	jsonSchema, err := New().Add(Response{}).WithInlineAnonymous(true).WithBackupDir("").ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "Address": {
+            "type": "object",
+            "properties": {
+                "duration": {"type": "number"},
+                "text": {"type": "string"},
+                "Text2": {"type": "string"}
+            },
+            "required": ["duration"]
+        },
+        "Response": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "type": "object",
+                    "properties": {
+                        "page": {"type": "integer"},
+                        "paging": {
+                            "anyOf": [
+                                {
+                                    "type": "object",
+                                    "properties": {"next": {"type": "string"}},
+                                    "required": ["next"]
+                                },
+                                {"type": "null"}
+                            ]
+                        }
+                    },
+                    "required": ["page"]
+                },
+                "items": {
+                    "anyOf": [
+                        {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "properties": {"address": {"$ref": "#/$defs/Address"}},
+                                "required": ["address"]
+                            }
+                        },
+                        {"type": "null"}
+                    ]
+                },
+                "by_key": {
+                    "anyOf": [
+                        {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "object",
+                                "properties": {
+                                    "address": {"anyOf": [{"$ref": "#/$defs/Address"}, {"type": "null"}]},
+                                    "count": {"type": "integer"}
+                                },
+                                "required": ["count"]
+                            }
+                        },
+                        {"type": "null"}
+                    ]
+                }
+            },
+            "required": ["meta", "items", "by_key"]
+        }
+    }
+}`, jsonSchema)
+}
+
+func TestAnonymousStructNameCollision(t *testing.T) {
+	t.Parallel()
+	type Response_Meta struct {
+		Page int `json:"page"`
+	}
+	type Responses struct {
+		Response Response      `json:"response"`
+		Meta     Response_Meta `json:"meta"`
+	}
+	_, err := New().Add(Responses{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "name collision: anonymous struct github.com/tkrajina/typescriptify-golang-structs/typescriptify.Response.Meta and github.com/tkrajina/typescriptify-golang-structs/typescriptify.Response_Meta are both named Response_Meta")
+
+	// Anonymous structs without a parent struct can't be named:
+	_, err = New().Add(struct{ Page int }{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "cannot name anonymous struct struct { Page int }")
+}
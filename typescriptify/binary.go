@@ -0,0 +1,78 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+const (
+	base64DecodeTransform = nullGuard + `Uint8Array.from(atob(__VALUE__), (c) => c.charCodeAt(0))`
+	base64EncodeSerialize = nullGuard + `btoa(Array.from(__VALUE__, (b) => String.fromCharCode(b)).join(""))`
+)
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// WithUint8Array sets if byte slices (encoded as base64 strings) are converted to `Uint8Array`, class constructors
+// decode them and `toJSON()` encodes them back. Interfaces (which are only the shape of the JSON) keep `string`.
+func (t *TypeScriptify) WithUint8Array(b bool) *TypeScriptify {
+	t.BytesAsUint8Array = b
+	return t
+}
+
+// WithRawMessageType sets the TypeScript type of `json.RawMessage` values (`unknown` by default).
+func (t *TypeScriptify) WithRawMessageType(tsType string) *TypeScriptify {
+	t.RawMessageType = tsType
+	return t
+}
+
+// isByteSlice checks if values of the type are encoded as base64 strings. Like in `encoding/json`, slices of bytes
+// with a custom encoding are encoded as arrays.
+func isByteSlice(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice || typ.Elem().Kind() != reflect.Uint8 {
+		return false
+	}
+	ptr, elemPtr := reflect.PtrTo(typ), reflect.PtrTo(typ.Elem())
+	for _, marshaler := range []reflect.Type{jsonMarshalerType, textMarshalerType} {
+		if ptr.Implements(marshaler) || elemPtr.Implements(marshaler) {
+			return false
+		}
+	}
+	return true
+}
+
+// builtinTypeOptions returns the options for types converted as if they were registered with `ManageType()`: byte
+// slices, `json.RawMessage` and 64-bit integers (see `WithInt64Mode()`).
+func (t *TypeScriptify) builtinTypeOptions(typ reflect.Type) (TypeOptions, bool) {
+	if opts, is := t.int64TypeOptions(typ); is {
+		return opts, true
+	}
+	switch {
+	case typ == rawMessageType:
+		opts := TypeOptions{TSType: t.RawMessageType}
+		switch t.RawMessageType {
+		case "unknown":
+			opts.TSZod = "z.unknown()"
+		case "any":
+			opts.TSZod = "z.any()"
+		}
+		return opts, true
+	case !isByteSlice(typ):
+		return TypeOptions{}, false
+	case t.BytesAsUint8Array && !t.CreateInterface:
+		return TypeOptions{
+			TSType:      "Uint8Array",
+			TSTransform: base64DecodeTransform,
+			TSSerialize: base64EncodeSerialize,
+			TSZod:       "z.string().transform((v) => Uint8Array.from(atob(v), (c) => c.charCodeAt(0)))",
+		}, true
+	}
+	return TypeOptions{TSType: "string", TSZod: "z.string()"}, true
+}
+
+// typeOptions returns the options of a type registered with `ManageType()`, or its builtin options.
+func (t *TypeScriptify) typeOptions(typ reflect.Type) (TypeOptions, bool) {
+	if opts, found := t.fieldTypeOptions[typ]; found {
+		return opts, true
+	}
+	return t.builtinTypeOptions(typ)
+}
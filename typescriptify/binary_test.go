@@ -0,0 +1,180 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Blob []byte
+
+type Attachment struct {
+	Data     []byte            `json:"data"`
+	Checksum Blob              `json:"checksum"`
+	Chunks   [][]byte          `json:"chunks"`
+	Fixed    [4]byte           `json:"fixed"`
+	Meta     json.RawMessage   `json:"meta"`
+	Named    map[string][]byte `json:"named"`
+}
+
+func TestByteSlices(t *testing.T) {
+	t.Parallel()
+
+	byts, err := json.Marshal(Attachment{Data: []byte{1, 2, 3}, Fixed: [4]byte{1, 2, 3, 4}, Meta: json.RawMessage(`{"a":1}`)})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"data":"AQID","checksum":null,"chunks":null,"fixed":[1,2,3,4],"meta":{"a":1},"named":null}`, string(byts))
+
+	converter := New().Add(Attachment{}).WithBackupDir("")
+	desiredResult := `export class Attachment {
+    data: string;
+    checksum: string;
+    chunks: string[];
+    fixed: number[];
+    meta: unknown;
+    named: {[key: string]: string};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.data = source["data"];
+        this.checksum = source["checksum"];
+        this.chunks = source["chunks"];
+        this.fixed = source["fixed"];
+        this.meta = source["meta"];
+        this.named = source["named"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Attachment({"data": "AQID"}).data === "AQID"`,
+	})
+
+	converter = New().Add(Attachment{}).WithRawMessageType("any").WithInterface(true).WithBackupDir("")
+	desiredResult = `export interface Attachment {
+    data: string;
+    checksum: string;
+    chunks: string[];
+    fixed: number[];
+    meta: any;
+    named: {[key: string]: string};
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestByteSlicesAsUint8Array(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Attachment{}).WithUint8Array(true).WithBackupDir("")
+	desiredResult := `export class Attachment {
+    data: Uint8Array;
+    checksum: Uint8Array;
+    chunks: Uint8Array[];
+    fixed: number[];
+    meta: unknown;
+    named: {[key: string]: Uint8Array};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.data = source["data"] == null ? source["data"] : Uint8Array.from(atob(source["data"]), (c) => c.charCodeAt(0));
+        this.checksum = source["checksum"] == null ? source["checksum"] : Uint8Array.from(atob(source["checksum"]), (c) => c.charCodeAt(0));
+        this.chunks = ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => e1 == null ? e1 : Uint8Array.from(atob(e1), (c) => c.charCodeAt(0))))(source["chunks"]);
+        this.fixed = source["fixed"];
+        this.meta = source["meta"];
+        this.named = ((v1: any) => { if (v1 == null) return v1; for (const k1 of Object.keys(v1)) v1[k1] = v1[k1] == null ? v1[k1] : Uint8Array.from(atob(v1[k1]), (c) => c.charCodeAt(0)); return v1; })(source["named"]);
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "data": this.data == null ? this.data : btoa(Array.from(this.data, (b) => String.fromCharCode(b)).join("")),
+            "checksum": this.checksum == null ? this.checksum : btoa(Array.from(this.checksum, (b) => String.fromCharCode(b)).join("")),
+            "chunks": ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => e1 == null ? e1 : btoa(Array.from(e1, (b) => String.fromCharCode(b)).join(""))))(this.chunks),
+            "named": ((v1: any) => { if (v1 == null) return v1; const r1: any = {}; for (const k1 of Object.keys(v1)) r1[k1] = v1[k1] == null ? v1[k1] : btoa(Array.from(v1[k1], (b) => String.fromCharCode(b)).join("")); return r1; })(this.named),
+        };
+    }
+}`
+	source := `{"data": "AQID", "checksum": "", "chunks": ["AQ==", "AgM="], "named": {"a": "BA=="}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Attachment(` + source + `).data instanceof Uint8Array`,
+		`new Attachment(` + source + `).data[2] === 3`,
+		`new Attachment(` + source + `).checksum.length === 0`,
+		`new Attachment(` + source + `).chunks[1][1] === 3`,
+		`new Attachment(` + source + `).named["a"][0] === 4`,
+		`JSON.stringify(new Attachment(` + source + `)) === JSON.stringify(` + source + `)`,
+		`new Attachment({}).data === undefined`,
+	})
+
+	// Interfaces are the shape of the JSON:
+	converter = New().Add(Attachment{}).WithUint8Array(true).WithInterface(true).WithBackupDir("")
+	desiredResult = `export interface Attachment {
+    data: string;
+    checksum: string;
+    chunks: string[];
+    fixed: number[];
+    meta: unknown;
+    named: {[key: string]: string};
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestByteSlicesZodGuardsAndJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Attachment{}).WithZodSchema(true).WithBackupDir("")
+	desiredResult := `import { z } from "zod";
+
+export const AttachmentSchema = z.object({
+    data: z.string(),
+    checksum: z.string(),
+    chunks: z.array(z.string()),
+    fixed: z.array(z.number()),
+    meta: z.unknown(),
+    named: z.record(z.string(), z.string()),
+});
+export type Attachment = z.infer<typeof AttachmentSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	converter = New().Add(Attachment{}).WithTypeGuards(true).WithInterface(true).WithBackupDir("")
+	desiredResult = `export interface Attachment {
+    data: string;
+    checksum: string;
+    chunks: string[];
+    fixed: number[];
+    meta: unknown;
+    named: {[key: string]: string};
+}
+export function isAttachment(x: unknown): x is Attachment {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return (o["data"] === null || "string" === typeof o["data"])
+        && (o["checksum"] === null || "string" === typeof o["checksum"])
+        && (o["chunks"] === null || Array.isArray(o["chunks"]) && o["chunks"].every((v1: unknown) => "string" === typeof v1))
+        && Array.isArray(o["fixed"]) && o["fixed"].every((v1: unknown) => "number" === typeof v1)
+        && (o["named"] === null || "object" === typeof o["named"] && o["named"] !== null && !Array.isArray(o["named"]) && Object.values(o["named"]).every((v1: unknown) => "string" === typeof v1));
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isAttachment({"data": "AQID", "checksum": "", "chunks": [], "fixed": [1, 2, 3, 4], "meta": null, "named": {}})`,
+		`!isAttachment({"data": [1, 2, 3], "checksum": "", "chunks": [], "fixed": [1, 2, 3, 4], "meta": null, "named": {}})`,
+	})
+
+	schema, err := New().Add(Attachment{}).WithBackupDir("").ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "Attachment": {
+            "type": "object",
+            "properties": {
+                "data": {"anyOf": [{"type": "string", "contentEncoding": "base64"}, {"type": "null"}]},
+                "checksum": {"anyOf": [{"type": "string", "contentEncoding": "base64"}, {"type": "null"}]},
+                "chunks": {"anyOf": [{"type": "array", "items": {"type": "string", "contentEncoding": "base64"}}, {"type": "null"}]},
+                "fixed": {"type": "array", "items": {"type": "integer"}, "minItems": 4, "maxItems": 4},
+                "meta": {},
+                "named": {"anyOf": [{"type": "object", "additionalProperties": {"type": "string", "contentEncoding": "base64"}}, {"type": "null"}]}
+            },
+            "required": ["data", "checksum", "chunks", "fixed", "meta", "named"]
+        }
+    }
+}`, schema)
+}
@@ -0,0 +1,98 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type constElement struct {
+	name  string
+	value interface{}
+}
+
+// AddConst adds a constant (a string, number or bool), it's converted to `export const name = value;`. Constants of
+// enums and type aliases are typed with them, values of managed types are converted with their `TSTransform`.
+func (t *TypeScriptify) AddConst(name string, value interface{}) *TypeScriptify {
+	t.consts = append(t.consts, constElement{name: name, value: value})
+	return t
+}
+
+// WithConstsModule sets the module of constants without a converted type in `ConvertFiles()` (`consts.ts` by default),
+// for example if a package named `consts` is converted too.
+func (t *TypeScriptify) WithConstsModule(moduleName string) *TypeScriptify {
+	t.ConstsModule = moduleName
+	return t
+}
+
+// convertConst returns the declaration of a constant, with the type alias it uses (if not yet converted).
+func (t *TypeScriptify) convertConst(depth int, c constElement) (string, error) {
+	t.logf(depth, "Converting const %s", c.name)
+	if c.value == nil {
+		return "", fmt.Errorf("const %s: nil can't be converted to a TypeScript literal", c.name)
+	}
+	typ := reflect.TypeOf(c.value)
+	t.beginDeclaration()
+
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+
+	if elements, isEnum := t.enums[typ]; isEnum {
+		for _, el := range elements {
+			if el.value != c.value {
+				continue
+			}
+			entityName := t.typeName(typ)
+			if t.EnumStyle == EnumUnion {
+				literal, err := t.enumValueLiteral(entityName, el)
+				if err != nil {
+					return "", err
+				}
+				return t.declare(typ, fmt.Sprintf("%sconst %s: %s = %s;", export, c.name, entityName, literal)), nil
+			}
+			return t.declare(typ, fmt.Sprintf("%sconst %s = %s.%s;", export, c.name, entityName, el.name)), nil
+		}
+		return "", fmt.Errorf("const %s: %v isn't a value of enum %s", c.name, c.value, t.typeName(typ))
+	}
+
+	literal, err := t.constLiteral(c.name, reflect.ValueOf(c.value))
+	if err != nil {
+		return "", err
+	}
+	if opts, found := t.fieldTypeOptions[typ]; found && opts.TSTransform != "" {
+		literal = strings.Replace(opts.TSTransform, "__VALUE__", literal, -1)
+	}
+	if !t.isPrimitiveAlias(typ) {
+		return t.declare(nil, fmt.Sprintf("%sconst %s = %s;", export, c.name, literal)), nil
+	}
+	alias := t.convertAlias(depth+1, typ)
+	if t.Aliases == BrandedTypeAliases {
+		// Literals aren't assignable to branded types without a cast:
+		return alias + t.declare(typ, fmt.Sprintf("%sconst %s = %s as %s;", export, c.name, literal, t.typeName(typ))), nil
+	}
+	return alias + t.declare(typ, fmt.Sprintf("%sconst %s: %s = %s;", export, c.name, t.typeName(typ), literal)), nil
+}
+
+// constLiteral returns the TypeScript literal of a constant value, 64-bit integers (and integers JavaScript numbers
+// can't represent exactly) follow `WithInt64Mode()`.
+func (t *TypeScriptify) constLiteral(name string, v reflect.Value) (string, error) {
+	literal, err := tsLiteral(v)
+	if err != nil {
+		return "", fmt.Errorf("const %s: %s", name, err.Error())
+	}
+	if is64Bit(v.Type()) || !isSafeInteger(v.Interface()) {
+		switch t.Int64Mode {
+		case Int64AsString:
+			return strconv.Quote(literal), nil
+		case Int64AsBigInt:
+			return fmt.Sprintf("BigInt(%q)", literal), nil
+		}
+	}
+	if !isSafeInteger(v.Interface()) {
+		return "", fmt.Errorf("const %s: %s can't be represented exactly by a JavaScript number (use WithInt64Mode(Int64AsString) or WithInt64Mode(Int64AsBigInt))", name, literal)
+	}
+	return literal, nil
+}
@@ -0,0 +1,224 @@
+package typescriptify
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/billing"
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/consts"
+)
+
+const (
+	MaxPageSize         = 100
+	APIKeyHeader        = "X-API-Key"
+	Debug               = false
+	Ratio               = 0.25
+	AdminID      UserID = "admin"
+	DefaultFee   Cents  = 30
+	FirstDay            = Monday
+)
+
+func TestConsts(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddEnum(allWeekdaysV1).
+		AddConst("MaxPageSize", MaxPageSize).
+		AddConst("APIKeyHeader", APIKeyHeader).
+		AddConst("Debug", Debug).
+		AddConst("Ratio", Ratio).
+		AddConst("Escaped", "\"quoted\"\n\u2028 `ž` <b>").
+		AddConst("Million", 1e6).
+		AddConst("Tiny", 1e-7).
+		AddConst("Huge", 1e21).
+		AddConst("NegativeZero", math.Copysign(0, -1)).
+		AddConst("AdminID", AdminID).
+		AddConst("DefaultFee", DefaultFee).
+		AddConst("FirstDay", FirstDay).
+		WithBackupDir("")
+	desiredResult := `export enum Weekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export const MaxPageSize = 100;
+export const APIKeyHeader = "X-API-Key";
+export const Debug = false;
+export const Ratio = 0.25;
+export const Escaped = "\"quoted\"\n\u2028 ` + "`ž`" + ` <b>";
+export const Million = 1000000;
+export const Tiny = 1e-7;
+export const Huge = 1e+21;
+export const NegativeZero = -0;
+export const AdminID = "admin";
+export const DefaultFee = 30;
+export const FirstDay = Weekday.MONDAY;`
+	testConverter(t, converter, false, desiredResult, []string{
+		`MaxPageSize === 100`,
+		`Escaped.length === 18`,
+		`Million === 1000000`,
+		`Tiny === 0.0000001`,
+		`String(Tiny) === "1e-7"`,
+		`String(Huge) === "1e+21"`,
+		`Object.is(NegativeZero, -0)`,
+		`FirstDay === Weekday.MONDAY`,
+	})
+}
+
+func TestConstsWithTypeAliases(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddConst("AdminID", AdminID).
+		AddConst("DefaultFee", DefaultFee).
+		WithTypeAliases(TypeAliases).
+		WithPrefix("API").
+		WithBackupDir("")
+	desiredResult := `export type APIUserID = string;
+export const AdminID: APIUserID = "admin";
+export type APICents = number;
+export const DefaultFee: APICents = 30;`
+	testConverter(t, converter, false, desiredResult, []string{
+		`AdminID === "admin"`,
+	})
+
+	converter = New().AddConst("AdminID", AdminID).WithTypeAliases(BrandedTypeAliases).WithBackupDir("")
+	desiredResult = `export type UserID = string & { readonly __brand: "UserID" };
+export const AdminID = "admin" as UserID;`
+	testConverter(t, converter, true, desiredResult, []string{
+		`AdminID === "admin"`,
+	})
+
+	// Decimal isn't declared, so the code isn't compiled:
+	converter = New().
+		AddConst("DefaultFee", DefaultFee).
+		WithTypeAliases(TypeAliases).
+		ManageType(Cents(0), TypeOptions{TSType: "Decimal", TSTransform: "new Decimal(__VALUE__)"}).
+		WithBackupDir("")
+	desiredResult = `export type Cents = Decimal;
+export const DefaultFee: Cents = new Decimal(30);`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestInt64Consts(t *testing.T) {
+	t.Parallel()
+
+	converter := New().AddConst("Small", int64(1)).AddConst("Inf", math.Inf(-1)).WithBackupDir("")
+	testConverter(t, converter, false, `export const Small = 1;
+export const Inf = -Infinity;`, []string{
+		`Inf === -Infinity`,
+	})
+
+	// Like enum values, integers above 2^53 aren't rounded silently:
+	for _, big := range []interface{}{uint64(1) << 62, uint(1) << 62} {
+		_, err := New().AddConst("Big", big).WithBackupDir("").Convert(nil)
+		assert.EqualError(t, err, "const Big: 4611686018427387904 can't be represented exactly by a JavaScript number (use WithInt64Mode(Int64AsString) or WithInt64Mode(Int64AsBigInt))")
+	}
+
+	// BigInt() needs the ES2020 lib, so the code isn't compiled with the default tsc settings here:
+	converter = New().AddConst("Small", int64(1)).AddConst("Big", uint64(1)<<62).AddConst("BigUint", uint(1)<<62).WithInt64Mode(Int64AsBigInt).WithBackupDir("")
+	testConvertedCode(t, converter, `export const Small = BigInt("1");
+export const Big = BigInt("4611686018427387904");
+export const BigUint = BigInt("4611686018427387904");`)
+
+	converter = New().AddConst("Small", int64(1)).AddConst("Big", uint64(1)<<62).AddConst("BigUint", uint(1)<<62).WithInt64Mode(Int64AsString).WithBackupDir("")
+	testConverter(t, converter, false, `export const Small = "1";
+export const Big = "4611686018427387904";
+export const BigUint = "4611686018427387904";`, []string{
+		`Big === "4611686018427387904"`,
+	})
+}
+
+func TestInvalidConsts(t *testing.T) {
+	t.Parallel()
+
+	_, err := New().AddConst("Address", Address{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "const Address: typescriptify.Address values can't be converted to TypeScript literals")
+
+	// Not named like the types of fields:
+	_, err = New().AddConst("Empty", struct{}{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "const Empty: struct {} values can't be converted to TypeScript literals")
+
+	_, err = New().AddConst("Nothing", nil).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "const Nothing: nil can't be converted to a TypeScript literal")
+
+	_, err = New().AddEnum(allWeekdaysV1).AddConst("Someday", Weekday(10)).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "const Someday: 10 isn't a value of enum Weekday")
+}
+
+func TestConstsInFiles(t *testing.T) {
+	t.Parallel()
+
+	files, err := New().
+		Add(billing.Invoice{}).
+		AddConst("MaxPageSize", MaxPageSize).
+		AddConst("AdminID", AdminID).
+		WithTypeAliases(TypeAliases).
+		WithInterface(true).
+		WithBackupDir("").
+		ConvertFiles(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"consts.ts": `
+export const MaxPageSize = 100;`,
+		"typescriptify.ts": `
+export type UserID = string;
+export const AdminID: UserID = "admin";`,
+		"typescriptify/testdata/modules/billing.ts": `import { Address, User } from './users';
+
+export interface InvoiceLine {
+    description: string;
+    amount: number;
+}
+export interface Invoice {
+    number: string;
+    customer: User;
+    lines: InvoiceLine[];
+    shipping?: Address;
+}`,
+		"typescriptify/testdata/modules/users.ts": `
+export interface Address {
+    street: string;
+    city: string;
+}
+export interface User {
+    name: string;
+    address: Address;
+}`,
+	}, files)
+}
+
+func TestConstsModuleCollision(t *testing.T) {
+	t.Parallel()
+
+	_, err := New().Add(consts.Limits{}).AddConst("MaxPageSize", MaxPageSize).WithBackupDir("").ConvertFiles(nil)
+	assert.EqualError(t, err, "module consts.ts of package github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/consts is also the module of constants without a converted type (see WithConstsModule())")
+
+	files, err := New().Add(consts.Limits{}).AddConst("MaxPageSize", MaxPageSize).WithConstsModule("constants.ts").WithBackupDir("").ConvertFiles(nil)
+	assert.Nil(t, err)
+	limits := `
+export class Limits {
+    max_page_size: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.max_page_size = source["max_page_size"];
+    }
+}`
+	assert.Equal(t, map[string]string{
+		"constants.ts": `
+export const MaxPageSize = 100;`,
+		"consts.ts": limits,
+	}, files)
+
+	// Without untyped constants there's no consts.ts module:
+	files, err = New().Add(consts.Limits{}).WithBackupDir("").ConvertFiles(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"consts.ts": limits}, files)
+}
@@ -0,0 +1,118 @@
+package typescriptify
+
+import (
+	"testing"
+)
+
+type BaseEntity struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+type Entity struct {
+	BaseEntity
+	Name string `json:"name"`
+}
+
+type EntityWithShadowedField struct {
+	BaseEntity
+	ID int `json:"id"`
+}
+
+func TestEmbeddedAsExtends(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Entity{}).
+		WithEmbeddedAsExtends(true).
+		WithBackupDir("")
+
+	desiredResult := `export class BaseEntity {
+    id: string;
+    version: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.id = source["id"];
+        this.version = source["version"];
+    }
+}
+export class Entity extends BaseEntity {
+    name: string;
+
+    constructor(source: any = {}) {
+        super(source);
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+    }
+}`
+	jsn := jsonizeOrPanic(Entity{BaseEntity: BaseEntity{ID: "aaa", Version: 7}, Name: "bbb"})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Entity(` + jsn + `) instanceof BaseEntity`,
+		`new Entity(` + jsn + `).id === "aaa"`,
+		`new Entity(` + jsn + `).version === 7`,
+		`new Entity(` + jsn + `).name === "bbb"`,
+		`new Entity(` + jsonizeOrPanic(jsn) + `).name === "bbb"`,
+	})
+}
+
+func TestEmbeddedAsExtendsWithInterfaces(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Entity{}).
+		Add(PersonWithPtrName{}).
+		WithEmbeddedAsExtends(true).
+		WithInterface(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface BaseEntity {
+    id: string;
+    version: number;
+}
+export interface Entity extends BaseEntity {
+    name: string;
+}
+export interface PersonWithPtrName {
+    name: string;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestEmbeddedAsExtendsWithNilPointer(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(PersonWithPtrName{}).
+		WithEmbeddedAsExtends(true).
+		WithBackupDir("")
+
+	// The fields of a nil embedded pointer are omitted, so `*HasName` can't be a base class:
+	desiredResult := `export class PersonWithPtrName {
+    name: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new PersonWithPtrName(` + jsonizeOrPanic(PersonWithPtrName{}) + `).name === undefined`,
+		`new PersonWithPtrName(` + jsonizeOrPanic(PersonWithPtrName{HasName: &HasName{Name: "aaa"}}) + `).name === "aaa"`,
+	})
+}
+
+func TestEmbeddedAsExtendsWithShadowedField(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(EntityWithShadowedField{}).
+		WithEmbeddedAsExtends(true).
+		WithInterface(true).
+		WithBackupDir("")
+
+	// `EntityWithShadowedField.ID` shadows `BaseEntity.ID`, so the fields must be flattened:
+	desiredResult := `export interface EntityWithShadowedField {
+    version: number;
+    id: number;
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`((e: EntityWithShadowedField) => e.id)({version: 1, id: 2}) === 2`,
+	})
+}
@@ -0,0 +1,180 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TSEnumProvider is implemented by enum types which list their own values (every value must implement `TSNamer`).
+// Enums used in the converted types are registered automatically, without `AddEnum()`.
+type TSEnumProvider interface {
+	TSEnumValues() []interface{}
+}
+
+var enumProviderType = reflect.TypeOf((*TSEnumProvider)(nil)).Elem()
+
+// EnumStyle defines how enums are declared.
+type EnumStyle int
+
+const (
+	// EnumDeclaration declares a TypeScript `enum` (default).
+	EnumDeclaration EnumStyle = iota
+	// ConstEnumDeclaration declares a `const enum`, its members are inlined by the TypeScript compiler.
+	ConstEnumDeclaration
+	// EnumUnion declares an union of the enum values (`type Weekday = 0 | 1 | ...`), without named members.
+	EnumUnion
+	// EnumObject declares a frozen `as const` object with the enum members, and an union type of its values with the
+	// same name.
+	EnumObject
+)
+
+// WithEnumStyle sets how enums are declared. Enum fields are typed with the declared (enum or union) type in all
+// styles.
+func (t *TypeScriptify) WithEnumStyle(style EnumStyle) *TypeScriptify {
+	t.EnumStyle = style
+	return t
+}
+
+// enumValueLiteral returns the TypeScript literal of an enum value.
+func (t *TypeScriptify) enumValueLiteral(entityName string, el enumElement) (string, error) {
+	v := reflect.ValueOf(el.value)
+	literal, err := tsLiteral(v)
+	if err != nil {
+		return "", fmt.Errorf("enum %s: %s", entityName, err.Error())
+	}
+	if v.Kind() == reflect.Bool && (t.EnumStyle == EnumDeclaration || t.EnumStyle == ConstEnumDeclaration) {
+		return "", fmt.Errorf("enum %s: enum members can't be booleans (use WithEnumStyle())", entityName)
+	}
+	if t.isQuotedEnum(v.Type()) {
+		return strconv.Quote(literal), nil
+	}
+	if !isSafeInteger(el.value) {
+		// Go encodes the value as a JSON number, and enum members can't be bigint values:
+		return "", fmt.Errorf("enum %s: %s is %s, JavaScript numbers can't represent it exactly (use WithInt64Mode(Int64AsString) and the `,string` option)", entityName, el.name, literal)
+	}
+	return literal, nil
+}
+
+// enumMemberRef returns the expression referencing an enum member, enums declared as unions have no members.
+func (t *TypeScriptify) enumMemberRef(entityName string, el enumElement, literal string) string {
+	if t.EnumStyle == EnumUnion {
+		return literal
+	}
+	return entityName + "." + el.name
+}
+
+// enumDeclaration returns the declaration of an enum in the configured style.
+func (t *TypeScriptify) enumDeclaration(export, entityName string, elements []enumElement, literals []string) string {
+	switch t.EnumStyle {
+	case EnumUnion:
+		return fmt.Sprintf("%stype %s = %s;", export, entityName, strings.Join(literals, " | "))
+	case EnumObject:
+		result := fmt.Sprintf("%sconst %s = Object.freeze({\n", export, entityName)
+		for n, el := range elements {
+			result += fmt.Sprintf("%s%s: %s,\n", t.Indent, tsPropertyName(el.name), literals[n])
+		}
+		result += "} as const);\n"
+		return result + fmt.Sprintf("%stype %s = (typeof %s)[keyof typeof %s];", export, entityName, entityName, entityName)
+	}
+	result := export + "enum " + entityName + " {\n"
+	if t.EnumStyle == ConstEnumDeclaration {
+		result = export + "const enum " + entityName + " {\n"
+	}
+	for n, el := range elements {
+		result += fmt.Sprintf("%s%s = %s,\n", t.Indent, el.name, literals[n])
+	}
+	return result + "}"
+}
+
+// enumZodSchema returns the schema of an enum, `z.nativeEnum()` needs the enum object at runtime.
+func (t *TypeScriptify) enumZodSchema(entityName string, literals []string) string {
+	switch t.EnumStyle {
+	case EnumDeclaration, EnumObject:
+		return fmt.Sprintf("z.nativeEnum(%s)", entityName)
+	}
+	if len(literals) == 1 {
+		return fmt.Sprintf("z.literal(%s)", literals[0])
+	}
+	var schemas []string
+	for _, literal := range literals {
+		schemas = append(schemas, fmt.Sprintf("z.literal(%s)", literal))
+	}
+	return "z.union([" + strings.Join(schemas, ", ") + "])"
+}
+
+// collectEnumProviders registers the enums (implementing `TSEnumProvider`) used in the converted types.
+func (t *TypeScriptify) collectEnumProviders() error {
+	visited := map[reflect.Type]bool{}
+	var walk func(typ reflect.Type) error
+	walk = func(typ reflect.Type) error {
+		if visited[typ] {
+			return nil
+		}
+		visited[typ] = true
+		if _, isEnum := t.enums[typ]; isEnum {
+			return nil
+		}
+		if typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface && reflect.PtrTo(typ).Implements(enumProviderType) {
+			return t.addProvidedEnum(typ)
+		}
+		if union, isUnion := t.unions[typ]; isUnion {
+			for _, variant := range union.Variants {
+				if err := walk(variant.Type); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		switch typ.Kind() {
+		case reflect.Map:
+			if err := walk(typ.Key()); err != nil {
+				return err
+			}
+			return walk(typ.Elem())
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			return walk(typ.Elem())
+		case reflect.Struct:
+			for _, field := range deepFields(typ) {
+				if t.getJSONFieldName(field, false) == "" && !isInlineMap(field) {
+					continue
+				}
+				if err := walk(field.Type); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, unionTyp := range t.unionTypes {
+		if err := walk(unionTyp); err != nil {
+			return err
+		}
+	}
+	for _, strctTyp := range t.structTypes {
+		if err := walk(strctTyp.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addProvidedEnum registers an enum with the values returned by its `TSEnumValues()`.
+func (t *TypeScriptify) addProvidedEnum(typ reflect.Type) error {
+	values := reflect.New(typ).Interface().(TSEnumProvider).TSEnumValues()
+	if len(values) == 0 {
+		return fmt.Errorf("%s.TSEnumValues() returned no values", typ.String())
+	}
+	for _, value := range values {
+		if reflect.TypeOf(value) != typ {
+			return fmt.Errorf("%s.TSEnumValues() returned a %T value", typ.String(), value)
+		}
+		if _, is := value.(TSNamer); !is {
+			return fmt.Errorf("%s has no TSName method", typ.String())
+		}
+	}
+	t.AddEnum(values)
+	return nil
+}
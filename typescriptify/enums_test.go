@@ -0,0 +1,282 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstEnum(t *testing.T) {
+	t.Parallel()
+
+	converter := New().AddEnum(allGenders).WithEnumStyle(ConstEnumDeclaration).WithInterface(true).WithBackupDir("")
+	desiredResult := `export const enum Gender {
+    MALE = "m",
+    FEMALE = "f",
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`Gender.MALE === "m"`,
+	})
+
+	// z.nativeEnum() needs the enum object, which const enums don't have:
+	converter = New().AddEnum(allGenders).WithEnumStyle(ConstEnumDeclaration).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export const enum Gender {
+    MALE = "m",
+    FEMALE = "f",
+}
+export const GenderSchema = z.union([z.literal("m"), z.literal("f")]);`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestEnumUnion(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		Add(Holliday{}).
+		AddEnum(allWeekdaysV1).
+		AddConst("FirstDay", FirstDay).
+		WithEnumStyle(EnumUnion).
+		WithInterface(true).
+		WithBackupDir("")
+	desiredResult := `export type Weekday = 0 | 1 | 2 | 3 | 4 | 5 | 6;
+export interface Holliday {
+    name: string;
+    weekday: Weekday;
+}
+export const FirstDay: Weekday = 1;`
+	testConverter(t, converter, true, desiredResult, []string{
+		`((h: Holliday) => h.weekday === 1)({name: "", weekday: FirstDay})`,
+	})
+
+	converter = New().AddEnum(allWeekdaysV1).WithEnumStyle(EnumUnion).WithTypeGuards(true).WithBackupDir("")
+	desiredResult = `export type Weekday = 0 | 1 | 2 | 3 | 4 | 5 | 6;
+export function isWeekday(x: unknown): x is Weekday {
+    return ([0, 1, 2, 3, 4, 5, 6] as unknown[]).includes(x);
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isWeekday(6)`,
+		`!isWeekday(7)`,
+	})
+
+	converter = New().AddEnum(allGenders).WithEnumStyle(EnumUnion).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export type Gender = "m" | "f";
+export const GenderSchema = z.union([z.literal("m"), z.literal("f")]);`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestEnumObject(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		Add(Holliday{}).
+		AddEnum(allWeekdaysV1).
+		AddConst("FirstDay", FirstDay).
+		WithEnumStyle(EnumObject).
+		WithPrefix("API").
+		WithBackupDir("")
+	desiredResult := `export const APIWeekday = Object.freeze({
+    SUNDAY: 0,
+    MONDAY: 1,
+    TUESDAY: 2,
+    WEDNESDAY: 3,
+    THURSDAY: 4,
+    FRIDAY: 5,
+    SATURDAY: 6,
+} as const);
+export type APIWeekday = (typeof APIWeekday)[keyof typeof APIWeekday];
+export class APIHolliday {
+    name: string;
+    weekday: APIWeekday;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.weekday = source["weekday"];
+    }
+}
+export const FirstDay = APIWeekday.MONDAY;`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new APIHolliday({"weekday": 1}).weekday === APIWeekday.MONDAY`,
+		`FirstDay === 1`,
+		`Object.isFrozen(APIWeekday)`,
+	})
+
+	converter = New().AddEnum(allWeekdaysV1).WithEnumStyle(EnumObject).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export const Weekday = Object.freeze({
+    SUNDAY: 0,
+    MONDAY: 1,
+    TUESDAY: 2,
+    WEDNESDAY: 3,
+    THURSDAY: 4,
+    FRIDAY: 5,
+    SATURDAY: 6,
+} as const);
+export type Weekday = (typeof Weekday)[keyof typeof Weekday];
+export const WeekdaySchema = z.nativeEnum(Weekday);`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityHigh
+)
+
+func (p Priority) TSName() string {
+	if p == PriorityHigh {
+		return "HIGH"
+	}
+	return "LOW"
+}
+
+func (Priority) TSEnumValues() []interface{} {
+	return []interface{}{PriorityLow, PriorityHigh}
+}
+
+type Ticket struct {
+	Priority   Priority          `json:"priority"`
+	Escalation *Priority         `json:"escalation"`
+	History    []Priority        `json:"history"`
+	Owners     map[Priority]bool `json:"owners"`
+}
+
+type BadEnum int
+
+func (BadEnum) TSEnumValues() []interface{} {
+	return []interface{}{1}
+}
+
+type EmptyEnum int
+
+func (EmptyEnum) TSEnumValues() []interface{} {
+	return nil
+}
+
+type UnnamedEnum int
+
+func (UnnamedEnum) TSEnumValues() []interface{} {
+	return []interface{}{UnnamedEnum(1)}
+}
+
+func TestEnumProvider(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Ticket{}).WithInterface(true).WithBackupDir("")
+	desiredResult := `export enum Priority {
+    LOW = 0,
+    HIGH = 1,
+}
+export interface Ticket {
+    priority: Priority;
+    escalation?: Priority;
+    history: Priority[];
+    owners: Partial<Record<Priority, boolean>>;
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`Priority.HIGH === 1`,
+	})
+
+	schema, err := New().Add(Ticket{}).WithBackupDir("").ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "Priority": {"enum": [0, 1]},
+        "Ticket": {
+            "type": "object",
+            "properties": {
+                "priority": {"$ref": "#/$defs/Priority"},
+                "escalation": {"anyOf": [{"$ref": "#/$defs/Priority"}, {"type": "null"}]},
+                "history": {"anyOf": [{"type": "array", "items": {"$ref": "#/$defs/Priority"}}, {"type": "null"}]},
+                "owners": {
+                    "anyOf": [
+                        {
+                            "type": "object",
+                            "propertyNames": {"pattern": "^-?[0-9]+$"},
+                            "additionalProperties": {"type": "boolean"}
+                        },
+                        {"type": "null"}
+                    ]
+                }
+            },
+            "required": ["priority", "history", "owners"]
+        }
+    }
+}`, schema)
+
+	_, err = New().Add(struct {
+		Bad BadEnum `json:"bad"`
+	}{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "typescriptify.BadEnum.TSEnumValues() returned a int value")
+
+	type Tickets struct {
+		Empty EmptyEnum `json:"empty"`
+	}
+	_, err = New().Add(Tickets{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "typescriptify.EmptyEnum.TSEnumValues() returned no values")
+
+	type Labels struct {
+		Unnamed []UnnamedEnum `json:"unnamed"`
+	}
+	_, err = New().Add(Labels{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "typescriptify.UnnamedEnum has no TSName method")
+}
+
+type Quote string
+
+type Scale float64
+
+type Toggle bool
+
+type Point struct{ X int }
+
+func TestEnumLiterals(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddEnum([]struct {
+			Value  Quote
+			TSName string
+		}{{"`ž`\n\"", "ESCAPED"}, {"\u2028", "SEPARATOR"}}).
+		AddEnum([]struct {
+			Value  Scale
+			TSName string
+		}{{1e6, "MEGA"}, {1e-7, "TINY"}, {0.5, "HALF"}}).
+		WithBackupDir("")
+	desiredResult := `export enum Quote {
+    ESCAPED = "` + "`ž`" + `\n\"",
+    SEPARATOR = "\u2028",
+}
+export enum Scale {
+    MEGA = 1000000,
+    TINY = 1e-7,
+    HALF = 0.5,
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`Quote.ESCAPED.length === 5`,
+		`Scale.MEGA === 1000000`,
+	})
+
+	toggles := []struct {
+		Value  Toggle
+		TSName string
+	}{{true, "ON"}, {false, "OFF"}}
+	_, err := New().AddEnum(toggles).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "enum Toggle: enum members can't be booleans (use WithEnumStyle())")
+	testConverter(t, New().AddEnum(toggles).WithEnumStyle(EnumUnion).WithBackupDir(""), true, "export type Toggle = true | false;", []string{
+		`((x: Toggle) => x)(true)`,
+	})
+
+	_, err = New().AddEnum([]struct {
+		Value  Point
+		TSName string
+	}{{Point{1}, "ONE"}}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "enum Point: typescriptify.Point values can't be converted to TypeScript literals")
+}
@@ -0,0 +1,330 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	tsIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+	timeType           = reflect.TypeOf(time.Time{})
+	durationType       = reflect.TypeOf(time.Duration(0))
+)
+
+// jsonTag is a parsed `json` struct tag.
+type jsonTag struct {
+	name    string
+	options []string
+}
+
+func parseJSONTag(tag string) jsonTag {
+	parts := strings.Split(tag, ",")
+	return jsonTag{name: parts[0], options: parts[1:]}
+}
+
+func (jt jsonTag) has(option string) bool {
+	for _, o := range jt.options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the value of a `key:value` option (json/v2 `format:`).
+func (jt jsonTag) value(key string) string {
+	for _, o := range jt.options {
+		if strings.HasPrefix(o, key+":") {
+			return strings.TrimPrefix(o, key+":")
+		}
+	}
+	return ""
+}
+
+// optional checks if the field may be missing in the JSON (`omitempty`, or json/v2 `omitzero`).
+func (jt jsonTag) optional() bool {
+	return jt.has("omitempty") || jt.has("omitzero")
+}
+
+// validName returns the name from the tag if encoding/json accepts it as a JSON field name, or "".
+func (jt jsonTag) validName() string {
+	if jt.name == "" {
+		return ""
+	}
+	for _, c := range jt.name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// Backslash and quote chars are reserved, but otherwise any punctuation chars are allowed in a tag name.
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return ""
+		}
+	}
+	return jt.name
+}
+
+// quotesValue checks if the field is encoded as a JSON string because of the `,string` option.
+func (jt jsonTag) quotesValue(typ reflect.Type) bool {
+	if !jt.has("string") {
+		return false
+	}
+	if typ.Name() == "" && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	}
+	return false
+}
+
+// isInlineMap checks if the field is a map with the json/v2 `inline` (or `unknown`) option, its entries are members of
+// the parent object.
+func isInlineMap(field reflect.StructField) bool {
+	tag := parseJSONTag(field.Tag.Get("json"))
+	typ := field.Type
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Map && (tag.has("inline") || tag.has("unknown"))
+}
+
+// inlineMapField returns the inline map field of a struct (see `isInlineMap()`).
+func inlineMapField(typ reflect.Type) (reflect.StructField, bool) {
+	for _, field := range deepFields(typ) {
+		if isInlineMap(field) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// indexSignatureType returns the type of the index signature for an inline map. All declared properties must be
+// assignable to it, so it's the union of the map value type and the field types (or `any` in classes, because their
+// methods are properties too).
+func (t *TypeScriptify) indexSignatureType(generic *genericStruct, valueType reflect.Type, fields []fieldInfo) string {
+	if !t.CreateInterface && !t.CreateZodSchema {
+		return "any"
+	}
+	types := []string{t.typeExpr(valueType)}
+	for _, fld := range fields {
+		tsType := fld.opts.TSType
+		if tsType == "" && generic != nil {
+			tsType, _ = t.genericTypeExpr(generic, fld.types)
+		}
+		if tsType == "" {
+			tsType = t.typeExpr(fld.types[0])
+		}
+		types = append(types, tsType)
+		if strings.HasSuffix(fld.name, "?") {
+			types = append(types, "undefined")
+		}
+	}
+
+	var union []string
+	seen := map[string]bool{}
+	for _, tsType := range types {
+		if tsType == "any" {
+			return "any"
+		}
+		if !seen[tsType] {
+			seen[tsType] = true
+			union = append(union, tsType)
+		}
+	}
+	return strings.Join(union, " | ")
+}
+
+// formatType returns the TypeScript type, zod schema and JSON Schema for a value encoded with a json/v2 `format:`
+// option, or is=false if the format doesn't apply to the type.
+func formatType(typ reflect.Type, format string) (tsType string, zodSchema string, schema map[string]interface{}, is bool) {
+	if format == "" {
+		return "", "", nil, false
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	numeric := false
+	switch {
+	case typ == timeType:
+		numeric = format == "unix" || format == "unixmilli" || format == "unixmicro" || format == "unixnano"
+	case typ == durationType:
+		if format != "units" && format != "sec" && format != "milli" && format != "micro" && format != "nano" {
+			return "", "", nil, false
+		}
+		numeric = format != "units"
+	case (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) && typ.Elem().Kind() == reflect.Uint8:
+		if format == "array" {
+			return "number[]", "z.array(z.number())", map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}}, true
+		}
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		if format != "nonfinite" {
+			return "", "", nil, false
+		}
+		// NaN and infinities are encoded as strings:
+		return "number | string", "z.union([z.number(), z.string()])", map[string]interface{}{"type": []interface{}{"number", "string"}}, true
+	default:
+		return "", "", nil, false
+	}
+	if numeric {
+		return "number", "z.number()", map[string]interface{}{"type": "number"}, true
+	}
+	return "string", "z.string()", map[string]interface{}{"type": "string"}, true
+}
+
+// deepFields returns the fields of a struct exactly like encoding/json encodes them: fields of embedded structs (and
+// json/v2 `inline` structs) are promoted, and of the fields with the same JSON name the one with the shortest depth wins (or the tagged one if
+// there are more of them on the same depth, otherwise all are ignored). Ignored (`json:"-"`) and unexported fields are
+// not included. Fields are in the order of their declarations, and their `Index` is the full index sequence.
+func deepFields(typeOf reflect.Type) []reflect.StructField {
+	if typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+	if typeOf.Kind() != reflect.Struct {
+		return []reflect.StructField{}
+	}
+
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var candidates []fieldCandidate
+	next := []embedded{{typ: typeOf}}
+	nextCount := map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 {
+		current := next
+		count := nextCount
+		next, nextCount = nil, map[reflect.Type]int{}
+
+		for _, emb := range current {
+			if visited[emb.typ] {
+				continue
+			}
+			visited[emb.typ] = true
+
+			for i := 0; i < emb.typ.NumField(); i++ {
+				field := emb.typ.Field(i)
+				fieldType := field.Type
+				if fieldType.Name() == "" && fieldType.Kind() == reflect.Ptr {
+					fieldType = fieldType.Elem()
+				}
+				if field.Anonymous {
+					if !field.IsExported() && fieldType.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !field.IsExported() {
+					continue
+				}
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name := parseJSONTag(tag).validName()
+				field.Index = append(append([]int{}, emb.index...), i)
+
+				inline := fieldType.Kind() == reflect.Struct && (parseJSONTag(tag).has("inline") || (field.Anonymous && name == ""))
+				if !inline {
+					c := fieldCandidate{field: field, name: name, tagged: name != ""}
+					if c.name == "" {
+						c.name = field.Name
+					}
+					candidates = append(candidates, c)
+					if count[emb.typ] > 1 {
+						// The same struct is embedded more than once on this depth, so its fields annihilate each other:
+						candidates = append(candidates, c)
+					}
+					continue
+				}
+
+				nextCount[fieldType]++
+				if nextCount[fieldType] == 1 {
+					next = append(next, embedded{typ: fieldType, index: field.Index})
+				}
+			}
+		}
+	}
+
+	byName := map[string][]fieldCandidate{}
+	for _, c := range candidates {
+		byName[c.name] = append(byName[c.name], c)
+	}
+
+	var result []reflect.StructField
+	for _, fields := range byName {
+		if dominant, ok := dominantField(fields); ok {
+			result = append(result, dominant)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Index, result[j].Index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return result
+}
+
+// fieldCandidate is a (possibly promoted) struct field with its JSON name.
+type fieldCandidate struct {
+	field  reflect.StructField
+	name   string
+	tagged bool
+}
+
+// dominantField returns the field encoded of all the fields with the same JSON name: the one with the shortest depth,
+// if there are more the tagged one, if there are more (or none of them is tagged) none.
+func dominantField(fields []fieldCandidate) (reflect.StructField, bool) {
+	depth := len(fields[0].field.Index)
+	var dominant []fieldCandidate
+	for _, f := range fields {
+		switch {
+		case len(f.field.Index) < depth:
+			depth = len(f.field.Index)
+			dominant = []fieldCandidate{f}
+		case len(f.field.Index) == depth:
+			dominant = append(dominant, f)
+		}
+	}
+	if len(dominant) > 1 {
+		var tagged []fieldCandidate
+		for _, f := range dominant {
+			if f.tagged {
+				tagged = append(tagged, f)
+			}
+		}
+		dominant = tagged
+	}
+	if len(dominant) != 1 {
+		return reflect.StructField{}, false
+	}
+	return dominant[0].field, true
+}
+
+// tsPropertyName returns a property name for a TypeScript declaration, quoted if it isn't a valid identifier.
+func tsPropertyName(name string) string {
+	if tsIdentifierRegexp.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// tsPropertyAccess returns the expression accessing a property of an object.
+func tsPropertyAccess(object, name string) string {
+	if tsIdentifierRegexp.MatchString(name) {
+		return object + "." + name
+	}
+	return fmt.Sprintf("%s[%q]", object, name)
+}
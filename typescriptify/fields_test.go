@@ -0,0 +1,302 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TagsConformance struct {
+	Plain      string
+	Named      string  `json:"named"`
+	Dash       string  `json:"-,"`
+	Ignored    string  `json:"-"`
+	OnlyOpts   string  `json:",omitempty"`
+	Quoted     int64   `json:"quoted,string"`
+	QuotedPtr  *bool   `json:",string"`
+	QuotedStr  string  `json:"quoted_str,string"`
+	LateOpts   float64 `json:"late,,omitempty"`
+	Spaced     string  `json:" spaced "`
+	Punctuated string  `json:"a-b.c"`
+	unexported string
+}
+
+type confBase struct {
+	ID   int
+	Name string
+	Note string `json:"note"`
+}
+
+type confDeep struct {
+	confBase
+	Level string
+}
+
+type confOther struct {
+	Name  string
+	Extra string
+	Kind  string `json:"kind"`
+}
+
+type confLevel struct {
+	confBase
+	Level string
+	Kind  string `json:"kind"`
+	Other string `json:"Extra"`
+}
+
+type ConfTagged struct {
+	X int `json:"x"`
+}
+
+type ConfCount int
+
+type confHidden int
+
+type EmbeddingConformance struct {
+	confDeep
+	*confOther
+	confLevel
+	ConfTagged `json:"tagged"`
+	ConfCount
+	confHidden
+	ID string `json:"ID"`
+}
+
+type ConfNode struct {
+	Value string `json:"value"`
+	*ConfNode
+}
+
+var tsPropertyRegexp = regexp.MustCompile(`(?m)^\s+("(?:[^"\\]|\\.)*"|[A-Za-z_$][\w$]*)(\??): ([^;]+);$`)
+
+// testJSONConformance checks that the fields of the generated interface are exactly the keys encoding/json creates for
+// the value (which must have all fields set).
+func testJSONConformance(t *testing.T, value interface{}) map[string]string {
+	byts, err := json.Marshal(value)
+	assert.Nil(t, err)
+	var encoded map[string]interface{}
+	assert.Nil(t, json.Unmarshal(byts, &encoded))
+	var expected []string
+	for key := range encoded {
+		expected = append(expected, key)
+	}
+	sort.Strings(expected)
+
+	code, err := New().Add(value).WithInterface(true).WithBackupDir("").Convert(nil)
+	assert.Nil(t, err)
+	declaration := regexp.MustCompile(fmt.Sprintf(`(?s)export interface %s \{\n(.*?)\n\}`, reflect.TypeOf(value).Name())).FindStringSubmatch(code)
+	if !assert.NotNil(t, declaration, code) {
+		return nil
+	}
+	types := map[string]string{}
+	var actual []string
+	for _, match := range tsPropertyRegexp.FindAllStringSubmatch(declaration[1], -1) {
+		name := match[1]
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+		actual = append(actual, name)
+		types[name+match[2]] = match[3]
+	}
+	sort.Strings(actual)
+
+	assert.Equal(t, expected, actual, code)
+	return types
+}
+
+func TestJSONTagsConformance(t *testing.T) {
+	t.Parallel()
+	b := true
+	types := testJSONConformance(t, TagsConformance{
+		Plain:      "a",
+		Named:      "b",
+		Dash:       "c",
+		Ignored:    "d",
+		OnlyOpts:   "e",
+		Quoted:     1,
+		QuotedPtr:  &b,
+		QuotedStr:  "f",
+		LateOpts:   1.5,
+		Spaced:     "h",
+		Punctuated: "i",
+		unexported: "j",
+	})
+	assert.Equal(t, map[string]string{
+		"Plain":      "string",
+		"named":      "string",
+		"-":          "string",
+		"OnlyOpts?":  "string",
+		"quoted":     "string",
+		"QuotedPtr?": "string",
+		"quoted_str": "string",
+		"late?":      "number",
+		" spaced ":   "string",
+		"a-b.c":      "string",
+	}, types)
+}
+
+func TestJSONEmbeddingConformance(t *testing.T) {
+	t.Parallel()
+	types := testJSONConformance(t, EmbeddingConformance{
+		confDeep:   confDeep{confBase: confBase{ID: 1, Name: "a", Note: "b"}, Level: "c"},
+		confOther:  &confOther{Name: "d", Extra: "e", Kind: "f"},
+		confLevel:  confLevel{confBase: confBase{ID: 2, Name: "g", Note: "h"}, Level: "i", Kind: "j", Other: "k"},
+		ConfTagged: ConfTagged{X: 1},
+		ConfCount:  2,
+		confHidden: 3,
+		ID:         "l",
+	})
+	assert.Equal(t, map[string]string{
+		"Name":      "string",
+		"Extra":     "string",
+		"tagged":    "ConfTagged",
+		"ConfCount": "number",
+		"ID":        "string",
+	}, types)
+
+	types = testJSONConformance(t, ConfNode{Value: "a", ConfNode: &ConfNode{Value: "b"}})
+	assert.Equal(t, map[string]string{"value": "string"}, types)
+}
+
+func TestQuotedPropertyNames(t *testing.T) {
+	t.Parallel()
+	type Quoted struct {
+		Dash  string  `json:"-,"`
+		Names []int64 `json:"first-names,omitempty"`
+		Count int     `json:"count,string"`
+	}
+	converter := New().Add(Quoted{}).WithBackupDir("")
+	desiredResult := `export class Quoted {
+    "-": string;
+    "first-names"?: number[];
+    count: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this["-"] = source["-"];
+        this["first-names"] = source["first-names"];
+        this.count = source["count"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Quoted({"-": "x", "count": "1"})["-"] === "x"`,
+	})
+}
+
+type V2Audit struct {
+	By string `json:"by"`
+	At int64  `json:"at"`
+}
+
+type V2Options struct {
+	Name     string            `json:"name"`
+	Nickname string            `json:"nickname,omitzero"`
+	Audit    V2Audit           `json:"audit,inline"`
+	Extra    map[string]int    `json:",inline"`
+	Created  time.Time         `json:"created,format:unix"`
+	Updated  time.Time         `json:"updated,format:RFC3339"`
+	Timeout  time.Duration     `json:"timeout,format:sec"`
+	Data     []byte            `json:"data,format:base64"`
+	Raw      []byte            `json:"raw,format:array"`
+	Ratio    float64           `json:"ratio,format:nonfinite"`
+	Labels   map[string]string `json:"labels,case:ignore"`
+}
+
+func TestJSONV2Options(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(V2Options{}).
+		WithBackupDir("")
+
+	desiredResult := `export class V2Options {
+    name: string;
+    nickname?: string;
+    by: string;
+    at: number;
+    created: number;
+    updated: string;
+    timeout: number;
+    data: string;
+    raw: number[];
+    ratio: number | string;
+    labels: {[key: string]: string};
+    [key: string]: any;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.nickname = source["nickname"];
+        this.by = source["by"];
+        this.at = source["at"];
+        this.created = source["created"];
+        this.updated = source["updated"];
+        this.timeout = source["timeout"];
+        this.data = source["data"];
+        this.raw = source["raw"];
+        this.ratio = source["ratio"];
+        this.labels = source["labels"];
+        for (const key of Object.keys(source)) {
+            if (!["name", "nickname", "by", "at", "created", "updated", "timeout", "data", "raw", "ratio", "labels"].includes(key)) this[key] = source[key];
+        }
+    }
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new V2Options({"name": "a", "by": "b", "retries": 3})["retries"] === 3`,
+		`new V2Options({"name": "a", "by": "b", "retries": 3}).by === "b"`,
+		`JSON.stringify(new V2Options({"name": "a", "retries": 3})).includes('"retries":3')`,
+	})
+}
+
+func TestJSONV2OptionsInterfaceZodAndSchema(t *testing.T) {
+	t.Parallel()
+	type WithExtras struct {
+		ID     int               `json:"id"`
+		Note   string            `json:"note,omitzero"`
+		Extras map[string]string `json:",unknown"`
+	}
+
+	converter := New().Add(WithExtras{}).WithInterface(true).WithBackupDir("")
+	desiredResult := `export interface WithExtras {
+    id: number;
+    note?: string;
+    [key: string]: string | number | undefined;
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`((x: WithExtras) => x["retries"])({id: 1, retries: "3"}) === "3"`,
+	})
+
+	converter = New().Add(WithExtras{}).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export const WithExtrasSchema = z.object({
+    id: z.number(),
+    note: z.string().optional(),
+}).catchall(z.string());
+export type WithExtras = z.infer<typeof WithExtrasSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	schema, err := New().Add(WithExtras{}).WithBackupDir("").ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "WithExtras": {
+            "type": "object",
+            "properties": {
+                "id": {"type": "integer"},
+                "note": {"type": "string"}
+            },
+            "required": ["id"],
+            "additionalProperties": {"type": "string"}
+        }
+    }
+}`, schema)
+}
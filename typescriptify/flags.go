@@ -0,0 +1,76 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AddFlagsEnum adds an enum of bit flags (see `AddEnum()`), every value must be a distinct power of two up to 2^31.
+// Besides the enum, `hasX()`, `addX()`, `removeX()` and `toXNames()` functions are created for values combining the
+// flags, and fields of the enum type hold any combination of them.
+func (t *TypeScriptify) AddFlagsEnum(values interface{}) *TypeScriptify {
+	t.AddEnum(values)
+	if t.flagEnums == nil {
+		t.flagEnums = map[reflect.Type]bool{}
+	}
+	t.flagEnums[t.enumTypes[len(t.enumTypes)-1].Type] = true
+	return t
+}
+
+// flagsMask checks that the values of a flags enum are distinct powers of two (which can be combined with JavaScript
+// bitwise operators), and returns the combination of all flags.
+func (t *TypeScriptify) flagsMask(entityName string, elements []enumElement) (uint64, error) {
+	var mask uint64
+	for _, el := range elements {
+		v := reflect.ValueOf(el.value)
+		var flag uint64
+		switch {
+		case v.CanInt() && v.Int() > 0:
+			flag = uint64(v.Int())
+		case v.CanUint():
+			flag = v.Uint()
+		default:
+			return 0, fmt.Errorf("flags enum %s: %s is %v, not a power of two", entityName, el.name, el.value)
+		}
+		if flag == 0 || flag&(flag-1) != 0 {
+			return 0, fmt.Errorf("flags enum %s: %s is %v, not a power of two", entityName, el.name, el.value)
+		}
+		if flag > 1<<31 {
+			return 0, fmt.Errorf("flags enum %s: %s is %v, JavaScript bitwise operators work only up to 2^31", entityName, el.name, el.value)
+		}
+		if mask&flag != 0 {
+			return 0, fmt.Errorf("flags enum %s: %s is %v, which is already used", entityName, el.name, el.value)
+		}
+		mask |= flag
+	}
+	return mask, nil
+}
+
+// flagsHelpers returns the functions combining and testing the flags of an enum. Results are converted with `>>> 0`
+// to unsigned integers, so that the flag 2^31 isn't negative.
+func (t *TypeScriptify) flagsHelpers(export, entityName string, elements []enumElement, refs []string) string {
+	var names []string
+	for n, el := range elements {
+		names = append(names, fmt.Sprintf("[%s, %q]", refs[n], el.name))
+	}
+	functions := []string{
+		fmt.Sprintf("function has%s(flags: number, flag: %s): boolean {\n%sreturn ((flags & flag) >>> 0) === flag;\n}", entityName, entityName, t.Indent),
+		fmt.Sprintf("function add%s(flags: number, flag: %s): number {\n%sreturn (flags | flag) >>> 0;\n}", entityName, entityName, t.Indent),
+		fmt.Sprintf("function remove%s(flags: number, flag: %s): number {\n%sreturn (flags & ~flag) >>> 0;\n}", entityName, entityName, t.Indent),
+		fmt.Sprintf("function to%sNames(flags: number): string[] {\n%sreturn ([%s] as [%s, string][]).filter(([flag]) => (flags & flag) !== 0).map(([, name]) => name);\n}", entityName, t.Indent, strings.Join(names, ", "), entityName),
+	}
+	return export + strings.Join(functions, "\n"+export)
+}
+
+// flagsGuard returns the type guard function for a flags enum, any combination of the flags is valid.
+func (t *TypeScriptify) flagsGuard(export, entityName string, mask uint64) string {
+	result := fmt.Sprintf("%sfunction %s(x: unknown): x is %s {\n", export, guardName(entityName), entityName)
+	result += fmt.Sprintf("%sreturn \"number\" === typeof x && Number.isInteger(x) && x >= 0 && x <= %d && (x & ~%d) === 0;\n", t.Indent, mask, mask)
+	return result + "}"
+}
+
+// flagsZodSchema returns the schema of a flags enum, any combination of the flags is valid.
+func flagsZodSchema(mask uint64) string {
+	return fmt.Sprintf("z.number().int().min(0).max(%d).refine((x) => (x & ~%d) === 0)", mask, mask)
+}
@@ -0,0 +1,155 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Perm uint32
+
+const (
+	PermRead  Perm = 1 << 0
+	PermWrite Perm = 1 << 1
+	PermAdmin Perm = 1 << 31
+)
+
+var allPerms = []struct {
+	Value  Perm
+	TSName string
+}{
+	{PermRead, "READ"},
+	{PermWrite, "WRITE"},
+	{PermAdmin, "ADMIN"},
+}
+
+type Member struct {
+	Perms Perm `json:"perms"`
+}
+
+// permFlags is the converted Perm enum with its helpers.
+const permFlags = `export enum Perm {
+    READ = 1,
+    WRITE = 2,
+    ADMIN = 2147483648,
+}
+export function hasPerm(flags: number, flag: Perm): boolean {
+    return ((flags & flag) >>> 0) === flag;
+}
+export function addPerm(flags: number, flag: Perm): number {
+    return (flags | flag) >>> 0;
+}
+export function removePerm(flags: number, flag: Perm): number {
+    return (flags & ~flag) >>> 0;
+}
+export function toPermNames(flags: number): string[] {
+    return ([[Perm.READ, "READ"], [Perm.WRITE, "WRITE"], [Perm.ADMIN, "ADMIN"]] as [Perm, string][]).filter(([flag]) => (flags & flag) !== 0).map(([, name]) => name);
+}`
+
+func TestFlagsEnum(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Member{}).AddFlagsEnum(allPerms).WithInterface(true).WithBackupDir("")
+	desiredResult := permFlags + `
+export interface Member {
+    perms: Perm;
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`hasPerm(Perm.READ | Perm.WRITE, Perm.WRITE)`,
+		`!hasPerm(Perm.READ, Perm.WRITE)`,
+		`hasPerm(addPerm(Perm.READ, Perm.ADMIN), Perm.ADMIN)`,
+		`addPerm(Perm.READ, Perm.ADMIN) === 2147483649`,
+		`removePerm(Perm.READ | Perm.WRITE, Perm.READ) === Perm.WRITE`,
+		`removePerm(addPerm(0, Perm.ADMIN), Perm.READ) === 2147483648`,
+		`toPermNames(addPerm(Perm.READ, Perm.ADMIN)).join() === "READ,ADMIN"`,
+		`((m: Member) => m.perms === 3)({perms: Perm.READ | Perm.WRITE})`,
+	})
+}
+
+func TestFlagsEnumGuardsZodAndJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Member{}).AddFlagsEnum(allPerms).WithTypeGuards(true).WithBackupDir("")
+	desiredResult := permFlags + `
+export function isPerm(x: unknown): x is Perm {
+    return "number" === typeof x && Number.isInteger(x) && x >= 0 && x <= 2147483651 && (x & ~2147483651) === 0;
+}
+export class Member {
+    perms: Perm;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.perms = source["perms"];
+    }
+}
+export function isMember(x: unknown): x is Member {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return isPerm(o["perms"]);
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isPerm(3)`,
+		`isPerm(2147483649)`,
+		`!isPerm(4)`,
+		`!isPerm(-1)`,
+		`isMember({"perms": 0})`,
+	})
+
+	converter = New().Add(Member{}).AddFlagsEnum(allPerms).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+` + permFlags + `
+export const PermSchema = z.number().int().min(0).max(2147483651).refine((x) => (x & ~2147483651) === 0);
+export const MemberSchema = z.object({
+    perms: PermSchema,
+});
+export type Member = z.infer<typeof MemberSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	schema, err := New().Add(Member{}).AddFlagsEnum(allPerms).WithBackupDir("").ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "Perm": {"type": "integer", "minimum": 0, "maximum": 2147483651},
+        "Member": {
+            "type": "object",
+            "properties": {
+                "perms": {"$ref": "#/$defs/Perm"}
+            },
+            "required": ["perms"]
+        }
+    }
+}`, schema)
+}
+
+func TestInvalidFlagsEnums(t *testing.T) {
+	t.Parallel()
+
+	type perms []struct {
+		Value  Perm
+		TSName string
+	}
+	for _, test := range []struct {
+		values perms
+		err    string
+	}{
+		{perms{{PermRead, "READ"}, {3, "BOTH"}}, "flags enum Perm: BOTH is 3, not a power of two"},
+		{perms{{0, "NONE"}, {PermRead, "READ"}}, "flags enum Perm: NONE is 0, not a power of two"},
+		{perms{{PermRead, "READ"}, {PermRead, "AGAIN"}}, "flags enum Perm: AGAIN is 1, which is already used"},
+	} {
+		_, err := New().AddFlagsEnum(test.values).WithBackupDir("").Convert(nil)
+		assert.EqualError(t, err, test.err)
+	}
+
+	_, err := New().AddFlagsEnum([]struct {
+		Value  int64
+		TSName string
+	}{{1 << 40, "HUGE"}}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "flags enum int64: HUGE is 1099511627776, JavaScript bitwise operators work only up to 2^31")
+
+	_, err = New().AddFlagsEnum(allPerms).WithEnumStyle(EnumUnion).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "flags enum Perm: values combining flags can't be typed with an union, use the EnumDeclaration or ConstEnumDeclaration style")
+}
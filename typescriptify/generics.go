@@ -0,0 +1,360 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// genericStruct stores everything inferred about one generic Go struct from its instantiations.
+//
+// Reflection doesn't know about type parameters, so they are inferred by comparing the type arguments (which are
+// part of the type name, i.e. `Page[github.com/x/models.User]`) with the field types of every instantiation.
+type genericStruct struct {
+	name           string
+	params         []string
+	instantiations []reflect.Type
+	args           map[reflect.Type][]string
+}
+
+// localTypeSuffixRegexp matches the suffix reflect adds to function-local types used as type arguments.
+var localTypeSuffixRegexp = regexp.MustCompile(`·\d+`)
+
+// splitTypeArgs splits a type name like `Pair[string,github.com/x/models.User]` into the base name and the
+// type arguments.
+func splitTypeArgs(name string) (string, []string) {
+	start := strings.Index(name, "[")
+	if start < 0 || !strings.HasSuffix(name, "]") {
+		return name, nil
+	}
+
+	var args []string
+	depth := 0
+	argStart := start + 1
+	for i := argStart; i < len(name)-1; i++ {
+		switch name[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, name[argStart:i])
+				argStart = i + 1
+			}
+		}
+	}
+	args = append(args, name[argStart:len(name)-1])
+
+	for n := range args {
+		args[n] = localTypeSuffixRegexp.ReplaceAllString(strings.TrimSpace(args[n]), "")
+	}
+	return name[:start], args
+}
+
+// qualifiedTypeName returns the type name in the same format reflect uses for type arguments.
+func qualifiedTypeName(typ reflect.Type) string {
+	if typ.Name() != "" {
+		if typ.PkgPath() != "" {
+			return typ.PkgPath() + "." + localTypeSuffixRegexp.ReplaceAllString(typ.Name(), "")
+		}
+		return typ.Name()
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return "*" + qualifiedTypeName(typ.Elem())
+	case reflect.Slice:
+		return "[]" + qualifiedTypeName(typ.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", typ.Len(), qualifiedTypeName(typ.Elem()))
+	case reflect.Map:
+		return "map[" + qualifiedTypeName(typ.Key()) + "]" + qualifiedTypeName(typ.Elem())
+	}
+	return typ.String()
+}
+
+func genericKey(typ reflect.Type) string {
+	name, _ := splitTypeArgs(typ.Name())
+	return typ.PkgPath() + "." + name
+}
+
+// collectGenerics finds all instantiations of generic structs reachable from the registered types.
+func (t *TypeScriptify) collectGenerics() {
+	t.generics = map[string]*genericStruct{}
+
+	visited := map[reflect.Type]bool{}
+	var walk func(typ reflect.Type)
+	walk = func(typ reflect.Type) {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			walk(typ.Elem())
+		case reflect.Map:
+			walk(typ.Key())
+			walk(typ.Elem())
+		case reflect.Struct:
+			if visited[typ] {
+				return
+			}
+			visited[typ] = true
+			if name, args := splitTypeArgs(typ.Name()); len(args) > 0 {
+				key := genericKey(typ)
+				g, found := t.generics[key]
+				if !found {
+					g = &genericStruct{name: name, args: map[reflect.Type][]string{}}
+					if len(args) == 1 {
+						g.params = []string{"T"}
+					} else {
+						for n := range args {
+							g.params = append(g.params, fmt.Sprintf("T%d", n+1))
+						}
+					}
+					t.generics[key] = g
+				}
+				g.instantiations = append(g.instantiations, typ)
+				g.args[typ] = args
+			}
+			for _, field := range deepFields(typ) {
+				walk(field.Type)
+			}
+		}
+	}
+
+	for _, strctTyp := range t.structTypes {
+		walk(strctTyp.Type)
+	}
+}
+
+// genericOf returns the generic struct for an instantiation, or nil if typ is not generic. With only one
+// instantiation, type parameters can't be told apart from fields which happen to have the same type as the type
+// argument (`Total int` in `Page[int]`), so it's converted as a non-generic struct.
+func (t *TypeScriptify) genericOf(typ reflect.Type) *genericStruct {
+	if typ.Kind() != reflect.Struct || t.generics == nil {
+		return nil
+	}
+	if g := t.generics[genericKey(typ)]; g != nil && len(g.instantiations) > 1 {
+		return g
+	}
+	return nil
+}
+
+// paramIndex returns the index of the type parameter if the types (one per instantiation) are all the type
+// argument of that instantiation, or -1 (also if g is nil).
+func (g *genericStruct) paramIndex(types []reflect.Type) int {
+	if g == nil {
+		return -1
+	}
+	for n := range g.params {
+		matches := true
+		for i, inst := range g.instantiations {
+			if qualifiedTypeName(types[i]) != g.args[inst][n] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return n
+		}
+	}
+	return -1
+}
+
+// fieldTypes returns the types of the n-th field in every instantiation.
+func (g *genericStruct) fieldTypes(n int) []reflect.Type {
+	var types []reflect.Type
+	for _, inst := range g.instantiations {
+		types = append(types, deepFields(inst)[n].Type)
+	}
+	return types
+}
+
+// argTypes returns the types of the type arguments of one instantiation. Arguments not used by any field
+// are nil.
+func (t *TypeScriptify) argTypes(typ reflect.Type) []reflect.Type {
+	g := t.genericOf(typ)
+	if g == nil {
+		return nil
+	}
+	instIndex := 0
+	for n, inst := range g.instantiations {
+		if inst == typ {
+			instIndex = n
+		}
+	}
+
+	result := make([]reflect.Type, len(g.params))
+	var find func(types []reflect.Type)
+	find = func(types []reflect.Type) {
+		if n := g.paramIndex(types); n >= 0 {
+			result[n] = types[instIndex]
+			return
+		}
+		switch types[0].Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			find(mapTypes(types, reflect.Type.Elem))
+		case reflect.Map:
+			find(mapTypes(types, reflect.Type.Key))
+			find(mapTypes(types, reflect.Type.Elem))
+		}
+	}
+	for n := range deepFields(typ) {
+		find(g.fieldTypes(n))
+	}
+	return result
+}
+
+func mapTypes(types []reflect.Type, f func(reflect.Type) reflect.Type) []reflect.Type {
+	result := make([]reflect.Type, len(types))
+	for n := range types {
+		result[n] = f(types[n])
+	}
+	return result
+}
+
+// structName returns the TypeScript name of the struct class/interface (without type arguments).
+func (t *TypeScriptify) structName(typ reflect.Type) string {
+	return t.typeName(typ)
+}
+
+// structRef returns the TypeScript type of a struct, and the class and functions converting the values of its type
+// arguments used to instantiate it in constructors.
+func (t *TypeScriptify) structRef(typ reflect.Type) (tsType string, class string, classArgs []string) {
+	name := t.reference(t.structName(typ))
+	if t.genericOf(typ) == nil {
+		return name, name, nil
+	}
+	var args []string
+	for _, argTyp := range t.argTypes(typ) {
+		if argTyp == nil {
+			args = append(args, "any")
+			continue
+		}
+		args = append(args, t.typeExpr(argTyp))
+	}
+	return name + "<" + strings.Join(args, ", ") + ">", name, t.typeArgConverters(nil, []reflect.Type{typ}, 1)
+}
+
+// typeArgConverters returns the functions converting the values of the type arguments of a generic struct, they are
+// passed to its constructor (`undefined` if the values don't need to be converted). types contains the struct in every
+// instantiation of the generic struct g being declared, g is nil outside of generic declarations.
+func (t *TypeScriptify) typeArgConverters(g *genericStruct, types []reflect.Type, depth int) []string {
+	nested := t.genericOf(types[0])
+	if nested == nil {
+		return nil
+	}
+	instArgs := make([][]reflect.Type, len(types))
+	for i := range types {
+		instArgs[i] = t.argTypes(types[i])
+	}
+	var converters []string
+	for n := range nested.params {
+		argTypes := make([]reflect.Type, len(types))
+		known := true
+		for i := range types {
+			argTypes[i] = instArgs[i][n]
+			known = known && argTypes[i] != nil
+		}
+		converter := "undefined"
+		if known {
+			v := fmt.Sprintf("v%d", depth)
+			if p := g.paramIndex(argTypes); p >= 0 {
+				converter = "convert" + g.params[p]
+			} else if initializer := t.valueInitializer(g, argTypes, v, depth+1); initializer != v {
+				converter = fmt.Sprintf("(%s: any) => %s", v, initializer)
+			}
+		}
+		converters = append(converters, converter)
+	}
+	return converters
+}
+
+// typeExpr returns a TypeScript type expression for types used as type arguments.
+func (t *TypeScriptify) typeExpr(typ reflect.Type) string {
+	if t.isPrimitiveAlias(typ) {
+		return t.typeRef(typ)
+	}
+	if opts, found := t.typeOptions(typ); found && opts.TSType != "" {
+		return opts.TSType
+	}
+	if tsType, _, is := t.marshalerType(typ); is {
+		return tsType
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.typeExpr(typ.Elem())
+	case reflect.Slice, reflect.Array:
+		return t.typeExpr(typ.Elem()) + "[]"
+	case reflect.Map:
+		return t.mapType(typ.Key(), t.typeExpr(typ.Elem()))
+	case reflect.Struct:
+		if t.isInlineStruct(typ) {
+			return t.inlineStructType(typ)
+		}
+		tsType, _, _ := t.structRef(typ)
+		return tsType
+	}
+	if _, isUnion := t.unions[typ]; isUnion {
+		return t.typeRef(typ)
+	}
+	if _, isEnum := t.enums[typ]; isEnum {
+		return t.typeRef(typ)
+	}
+	if tsType, found := t.kinds[typ.Kind()]; found {
+		return tsType
+	}
+	return "any"
+}
+
+// genericTypeExpr returns the TypeScript type for a field of a generic struct declaration, types contains the field
+// type in every instantiation.
+func (t *TypeScriptify) genericTypeExpr(g *genericStruct, types []reflect.Type) (string, bool) {
+	if n := g.paramIndex(types); n >= 0 {
+		return g.params[n], true
+	}
+	switch types[0].Kind() {
+	case reflect.Ptr:
+		return t.genericTypeExpr(g, mapTypes(types, reflect.Type.Elem))
+	case reflect.Slice, reflect.Array:
+		expr, hasParams := t.genericTypeExpr(g, mapTypes(types, reflect.Type.Elem))
+		return expr + "[]", hasParams
+	case reflect.Map:
+		expr, hasParams := t.genericTypeExpr(g, mapTypes(types, reflect.Type.Elem))
+		return t.mapType(types[0].Key(), expr), hasParams
+	case reflect.Struct:
+		if nested := t.genericOf(types[0]); nested != nil {
+			nestedArgs := make([][]reflect.Type, len(types))
+			for i := range types {
+				nestedArgs[i] = t.argTypes(types[i])
+			}
+			var args []string
+			hasParams := false
+			for n := range nested.params {
+				argTypes := make([]reflect.Type, len(types))
+				known := true
+				for i := range types {
+					argTypes[i] = nestedArgs[i][n]
+					known = known && argTypes[i] != nil
+				}
+				if !known {
+					args = append(args, "any")
+					continue
+				}
+				arg, argHasParams := t.genericTypeExpr(g, argTypes)
+				args = append(args, arg)
+				hasParams = hasParams || argHasParams
+			}
+			return t.reference(t.structName(types[0])) + "<" + strings.Join(args, ", ") + ">", hasParams
+		}
+	}
+	return t.typeExpr(types[0]), false
+}
+
+// convertValuesCall returns the `this.convertValues(...)` call used in constructors.
+func convertValuesCall(source, class string, asMap bool, classArgs []string) string {
+	args := []string{source, class}
+	if asMap || len(classArgs) > 0 {
+		args = append(args, fmt.Sprint(asMap))
+	}
+	args = append(args, classArgs...)
+	return "this.convertValues(" + strings.Join(args, ", ") + ")"
+}
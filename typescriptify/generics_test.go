@@ -0,0 +1,265 @@
+package typescriptify
+
+import (
+	"testing"
+	"time"
+)
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+type Result[T any] struct {
+	Value *T     `json:"value"`
+	Error string `json:"error"`
+}
+
+type Listings struct {
+	Names   Page[HasName]         `json:"names"`
+	Dummies Page[Dummy]           `json:"dummies"`
+	Result  Result[Page[HasName]] `json:"result"`
+	Counts  Result[int]           `json:"counts"`
+}
+
+func TestGenerics(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Listings{}).
+		WithBackupDir("")
+
+	desiredResult := `export class Result<T> {
+    value?: T;
+    error: string;
+
+    constructor(source: any = {}, convertT: (v: any) => any = (v) => v) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.value = convertT(source["value"]);
+        this.error = source["error"];
+    }
+}
+export class Dummy {
+    something: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.something = source["something"];
+    }
+}
+export class HasName {
+    name: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+    }
+}
+export class Page<T> {
+    items: T[];
+    total: number;
+
+    constructor(source: any = {}, convertT: (v: any) => any = (v) => v) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.items = ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => convertT(e1)))(source["items"]);
+        this.total = source["total"];
+    }
+}
+export class Listings {
+    names: Page<HasName>;
+    dummies: Page<Dummy>;
+    result: Result<Page<HasName>>;
+    counts: Result<number>;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.names = this.convertValues(source["names"], Page, false, (v1: any) => this.convertValues(v1, HasName));
+        this.dummies = this.convertValues(source["dummies"], Page, false, (v1: any) => this.convertValues(v1, Dummy));
+        this.result = this.convertValues(source["result"], Result, false, (v1: any) => this.convertValues(v1, Page, false, (v2: any) => this.convertValues(v2, HasName)));
+        this.counts = this.convertValues(source["counts"], Result, false, undefined);
+    }
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := jsonizeOrPanic(Listings{
+		Names:   Page[HasName]{Items: []HasName{{Name: "aaa"}}, Total: 1},
+		Dummies: Page[Dummy]{Items: []Dummy{{Something: "bbb"}}, Total: 1},
+		Result:  Result[Page[HasName]]{Value: &Page[HasName]{Items: []HasName{{Name: "ccc"}}}},
+		Counts:  Result[int]{Value: new(int)},
+	})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Listings(` + jsn + `).names.items[0] instanceof HasName`,
+		`new Listings(` + jsn + `).names.items[0].name === "aaa"`,
+		`new Listings(` + jsn + `).dummies.items[0] instanceof Dummy`,
+		`new Listings(` + jsn + `).result.value instanceof Page`,
+		`new Listings(` + jsn + `).result.value!.items[0] instanceof HasName`,
+		`new Listings(` + jsn + `).result.value!.items[0].name === "ccc"`,
+		`new Listings(` + jsn + `).counts.value === 0`,
+	})
+}
+
+type Pair[K comparable, V any] struct {
+	Key    K            `json:"key"`
+	Values map[string]V `json:"values"`
+	Count  int          `json:"count"`
+}
+
+func TestGenericsWithInterfaces(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Pair[string, Dummy]{}).
+		Add(Pair[int, []string]{}).
+		WithInterface(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface Dummy {
+    something: string;
+}
+export interface Pair<T1, T2> {
+    key: T1;
+    values: {[key: string]: T2};
+    count: number;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+type TitledPage[T any] struct {
+	Items []T    `json:"items"`
+	Title string `json:"title"`
+}
+
+func TestGenericsWithOneInstantiation(t *testing.T) {
+	t.Parallel()
+
+	// Title has the same type as the type argument, with one instantiation it's not a type parameter:
+	converter := New().
+		Add(TitledPage[string]{}).
+		Add(Page[int]{}).
+		WithInterface(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface TitledPage {
+    items: string[];
+    title: string;
+}
+export interface Page {
+    items: number[];
+    total: number;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+type Timeline struct {
+	Dates Page[time.Time] `json:"dates"`
+	Names Page[HasName]   `json:"names"`
+}
+
+func TestGenericsWithManagedTypeArguments(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Timeline{}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)"}).
+		WithBackupDir("")
+
+	desiredResult := `export class HasName {
+    name: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+    }
+}
+export class Page<T> {
+    items: T[];
+    total: number;
+
+    constructor(source: any = {}, convertT: (v: any) => any = (v) => v) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.items = ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => convertT(e1)))(source["items"]);
+        this.total = source["total"];
+    }
+}
+export class Timeline {
+    dates: Page<Date>;
+    names: Page<HasName>;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.dates = this.convertValues(source["dates"], Page, false, (v1: any) => new Date(v1));
+        this.names = this.convertValues(source["names"], Page, false, (v1: any) => this.convertValues(v1, HasName));
+    }
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := `{"dates": {"items": ["2021-01-02T03:04:05.000Z"], "total": 1}, "names": {"items": [{"name": "aaa"}], "total": 1}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Timeline(` + jsn + `).dates.items[0] instanceof Date`,
+		`new Timeline(` + jsn + `).dates.items[0].toISOString() === "2021-01-02T03:04:05.000Z"`,
+		`new Timeline(` + jsn + `).names.items[0] instanceof HasName`,
+	})
+}
+
+type Grouped[T any] struct {
+	Groups map[string]map[string]T `json:"groups"`
+	Rows   []map[string]T          `json:"rows"`
+}
+
+type Groups struct {
+	Names  Grouped[HasName] `json:"names"`
+	Counts Grouped[int]     `json:"counts"`
+}
+
+func TestGenericsWithNestedContainers(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Groups{}).
+		WithBackupDir("")
+
+	desiredResult := `export class HasName {
+    name: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+    }
+}
+export class Grouped<T> {
+    groups: {[key: string]: {[key: string]: T}};
+    rows: {[key: string]: T}[];
+
+    constructor(source: any = {}, convertT: (v: any) => any = (v) => v) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.groups = ((v1: any) => { if (v1 == null) return v1; for (const k1 of Object.keys(v1)) v1[k1] = ((v2: any) => { if (v2 == null) return v2; for (const k2 of Object.keys(v2)) v2[k2] = convertT(v2[k2]); return v2; })(v1[k1]); return v1; })(source["groups"]);
+        this.rows = ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => ((v2: any) => { if (v2 == null) return v2; for (const k2 of Object.keys(v2)) v2[k2] = convertT(v2[k2]); return v2; })(e1)))(source["rows"]);
+    }
+}
+export class Groups {
+    names: Grouped<HasName>;
+    counts: Grouped<number>;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.names = this.convertValues(source["names"], Grouped, false, (v1: any) => this.convertValues(v1, HasName));
+        this.counts = this.convertValues(source["counts"], Grouped, false, undefined);
+    }
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := jsonizeOrPanic(Groups{
+		Names: Grouped[HasName]{
+			Groups: map[string]map[string]HasName{"a": {"b": {Name: "aaa"}}},
+			Rows:   []map[string]HasName{{"c": {Name: "ccc"}}},
+		},
+		Counts: Grouped[int]{
+			Groups: map[string]map[string]int{"a": {"b": 1}},
+			Rows:   []map[string]int{{"c": 2}},
+		},
+	})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Groups(` + jsn + `).names.groups["a"]["b"] instanceof HasName`,
+		`new Groups(` + jsn + `).names.groups["a"]["b"].name === "aaa"`,
+		`new Groups(` + jsn + `).names.rows[0]["c"] instanceof HasName`,
+		`new Groups(` + jsn + `).names.rows[0]["c"].name === "ccc"`,
+		`new Groups(` + jsn + `).counts.groups["a"]["b"] === 1`,
+		`new Groups(` + jsn + `).counts.rows[0]["c"] === 2`,
+	})
+}
@@ -0,0 +1,470 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithTypeGuards creates a `isStruct(x: unknown): x is Struct` function for every converted struct, enum and union.
+func (t *TypeScriptify) WithTypeGuards(b bool) *TypeScriptify {
+	t.CreateTypeGuards = b
+	return t
+}
+
+func guardName(entityName string) string {
+	return "is" + entityName
+}
+
+// guardExpr returns the expression checking that value is of the given type, types contains the type in every
+// instantiation of the generic struct g (or just one type if g is nil).
+func (t *TypeScriptify) guardExpr(g *genericStruct, types []reflect.Type, value string, depth int) string {
+	typ := types[0]
+	if g != nil {
+		if n := g.paramIndex(types); n >= 0 {
+			return fmt.Sprintf("%s(%s)", guardName(g.params[n]), value)
+		}
+	}
+	if t.isPrimitiveAlias(typ) {
+		return fmt.Sprintf("%s(%s)", t.reference(guardName(t.typeName(typ))), value)
+	}
+	if opts, found := t.fieldTypeOptions[typ]; found && opts.TSType != "" {
+		// Custom types can't be checked
+		return "true"
+	}
+	if opts, found := t.builtinTypeOptions(typ); found {
+		// Guards check the JSON, before class constructors parse bigint and Uint8Array values (from strings):
+		switch opts.TSType {
+		case "string", "bigint", "Uint8Array":
+			return fmt.Sprintf(`"string" === typeof %s`, value)
+		}
+		return "true"
+	}
+	switch t.marshalers[typ] {
+	case "string", "number", "boolean":
+		return fmt.Sprintf(`"%s" === typeof %s`, t.marshalers[typ], value)
+	case "array":
+		return fmt.Sprintf("Array.isArray(%s)", value)
+	case "object":
+		return fmt.Sprintf(`"object" === typeof %s && %s !== null && !Array.isArray(%s)`, value, value, value)
+	}
+	if _, isEnum := t.enums[typ]; isEnum {
+		return fmt.Sprintf("%s(%s)", t.reference(guardName(t.typeName(typ))), value)
+	}
+	if union, isUnion := t.unions[typ]; isUnion {
+		return fmt.Sprintf("%s(%s)", t.reference(guardName(t.typeName(union.Type))), value)
+	}
+
+	elem := fmt.Sprintf("v%d", depth+1)
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.guardExpr(g, mapTypes(types, reflect.Type.Elem), value, depth)
+	case reflect.Slice, reflect.Array:
+		result := fmt.Sprintf("Array.isArray(%s)", value)
+		if elemGuard := t.guardExpr(g, mapTypes(types, reflect.Type.Elem), elem, depth+1); elemGuard != "true" {
+			result += fmt.Sprintf(" && %s.every((%s: unknown) => %s)", value, elem, elemGuard)
+		}
+		return result
+	case reflect.Map:
+		result := fmt.Sprintf(`"object" === typeof %s && %s !== null && !Array.isArray(%s)`, value, value, value)
+		if elemGuard := t.guardExpr(g, mapTypes(types, reflect.Type.Elem), elem, depth+1); elemGuard != "true" {
+			result += fmt.Sprintf(" && Object.values(%s).every((%s: unknown) => %s)", value, elem, elemGuard)
+		}
+		if _, isEnum, _ := t.mapKeyType(typ.Key()); isEnum && typ.Key().Kind() == reflect.String {
+			result += fmt.Sprintf(" && Object.keys(%s).every((%s: string) => %s)", value, elem, fmt.Sprintf("%s(%s)", t.reference(guardName(t.typeName(typ.Key()))), elem))
+		}
+		return result
+	case reflect.Struct:
+		if t.isInlineStruct(typ) {
+			object := fmt.Sprintf("o%d", depth+1)
+			conditions := []string{fmt.Sprintf(`"object" === typeof %s`, object), object + " !== null"}
+			conditions = append(conditions, t.guardConditions(typ, nil, t.structFields(typ), object, depth+1)...)
+			return fmt.Sprintf("((%s: any) => %s)(%s)", object, strings.Join(conditions, " && "), value)
+		}
+		nested := t.genericOf(typ)
+		if nested == nil {
+			return fmt.Sprintf("%s(%s)", t.reference(guardName(t.structName(typ))), value)
+		}
+		args := []string{value}
+		for n := range nested.params {
+			argTypes := make([]reflect.Type, len(types))
+			known := true
+			for i := range types {
+				argTypes[i] = t.argTypes(types[i])[n]
+				known = known && argTypes[i] != nil
+			}
+			if !known {
+				args = append(args, fmt.Sprintf("(%s: unknown): %s is any => true", elem, elem))
+				continue
+			}
+			argType, _ := t.jsonTypeExpr(g, argTypes, nil)
+			args = append(args, fmt.Sprintf("(%s: unknown): %s is %s => %s", elem, elem, argType, t.guardExpr(g, argTypes, elem, depth+1)))
+		}
+		return fmt.Sprintf("%s(%s)", t.reference(guardName(t.structName(typ))), strings.Join(args, ", "))
+	}
+
+	switch tsType := t.kinds[typ.Kind()]; tsType {
+	case "string", "number", "boolean":
+		return fmt.Sprintf(`"%s" === typeof %s`, tsType, value)
+	}
+	return "true"
+}
+
+// implicitTSType returns the TypeScript type of a type with a custom JSON encoding or builtin options (which can be
+// checked, unlike custom types), or "".
+func (t *TypeScriptify) implicitTSType(typ reflect.Type) string {
+	if tsType, _, is := t.marshalerType(typ); is {
+		return tsType
+	}
+	opts, _ := t.builtinTypeOptions(typ)
+	return opts.TSType
+}
+
+// guardConditions returns the conditions checking the struct fields of an object.
+func (t *TypeScriptify) guardConditions(typeOf reflect.Type, generic *genericStruct, fields []fieldInfo, object string, depth int) []string {
+	var conditions []string
+	for _, fld := range fields {
+		name := strings.ReplaceAll(fld.name, "?", "")
+		value := fmt.Sprintf(`%s["%s"]`, object, name)
+		var condition string
+		if discriminator, is := t.discriminatorValue(typeOf, name); is {
+			condition = fmt.Sprintf("%s === %q", value, discriminator)
+		} else if fld.quoted {
+			condition = fmt.Sprintf(`"string" === typeof %s`, value)
+		} else if fld.opts.TSType != "" && fld.opts.TSType != t.implicitTSType(fld.types[0]) && !t.isAliasField(fld.types[0], fld.opts) {
+			// Custom types can't be checked
+			condition = value + " !== undefined"
+		} else {
+			condition = t.guardExpr(generic, fld.types, value, depth)
+			if condition == "true" {
+				continue
+			}
+		}
+		if strings.HasSuffix(fld.name, "?") {
+			if condition == value+" !== undefined" {
+				continue
+			}
+			if fld.canBeNull {
+				condition = fmt.Sprintf("(%s === undefined || %s === null || %s)", value, value, condition)
+			} else {
+				condition = fmt.Sprintf("(%s === undefined || %s)", value, condition)
+			}
+		} else if fld.canBeNull && condition != value+" !== undefined" {
+			condition = fmt.Sprintf("(%s === null || %s)", value, condition)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
+
+// structGuard returns the type guard function for a converted struct.
+func (t *TypeScriptify) structGuard(typeOf reflect.Type, entityName string, generic *genericStruct, bases []reflect.Type, fields []fieldInfo) string {
+	var conditions []string
+	for _, base := range bases {
+		conditions = append(conditions, t.guardExpr(nil, []reflect.Type{base}, "x", 0))
+	}
+	conditions = append(conditions, t.guardConditions(typeOf, generic, fields, "o", 0)...)
+
+	typeParams, guardParams := "", ""
+	if generic != nil {
+		typeParams = "<" + strings.Join(generic.params, ", ") + ">"
+		for _, param := range generic.params {
+			guardParams += fmt.Sprintf(", %s: (x: unknown) => x is %s", guardName(param), param)
+		}
+	}
+
+	jsonInterface := ""
+	typeName := entityName + typeParams
+	if t.hasJSONType(typeOf, nil) {
+		jsonInterface = t.jsonInterface(typeOf, entityName, typeParams, generic, bases, fields) + "\n"
+		typeName = jsonName(entityName) + typeParams
+	}
+
+	result := fmt.Sprintf("function %s%s(x: unknown%s): x is %s {\n", guardName(entityName), typeParams, guardParams, typeName)
+	result += fmt.Sprintf("%sif (\"object\" !== typeof x || x === null) {\n%s%sreturn false;\n%s}\n", t.Indent, t.Indent, t.Indent, t.Indent)
+	if len(conditions) == 0 {
+		result += t.Indent + "return true;\n"
+	} else {
+		result += fmt.Sprintf("%sconst o = x as any;\n", t.Indent)
+		result += fmt.Sprintf("%sreturn %s;\n", t.Indent, strings.Join(conditions, "\n"+t.Indent+t.Indent+"&& "))
+	}
+	result += "}"
+	if !t.DontExport {
+		result = "export " + result
+	}
+	return jsonInterface + result
+}
+
+// enumGuard returns the type guard function for an enum, values are the references to its members.
+func (t *TypeScriptify) enumGuard(entityName string, values []string) string {
+	result := fmt.Sprintf("function %s(x: unknown): x is %s {\n", guardName(entityName), entityName)
+	result += fmt.Sprintf("%sreturn ([%s] as unknown[]).includes(x);\n", t.Indent, strings.Join(values, ", "))
+	result += "}"
+	if !t.DontExport {
+		result = "export " + result
+	}
+	return result
+}
+
+// unionGuard returns the type guard function for an union, and the type of its JSON if the JSON of a variant doesn't
+// match its class.
+func (t *TypeScriptify) unionGuard(union UnionType, entityName string, variantNames []string) string {
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+	var conditions []string
+	for _, variantName := range variantNames {
+		conditions = append(conditions, t.reference(guardName(variantName))+"(x)")
+	}
+	result, typeName := "", entityName
+	if t.hasJSONType(union.Type, nil) {
+		typeName = jsonName(entityName)
+		var variantTypes []string
+		for _, variant := range union.Variants {
+			variantType, _ := t.jsonTypeExpr(nil, []reflect.Type{variant.Type}, nil)
+			variantTypes = append(variantTypes, variantType)
+		}
+		result += fmt.Sprintf("%stype %s = %s;\n", export, typeName, strings.Join(variantTypes, " | "))
+	}
+	result += fmt.Sprintf("%sfunction %s(x: unknown): x is %s {\n", export, guardName(entityName), typeName)
+	result += fmt.Sprintf("%sreturn %s;\n", t.Indent, strings.Join(conditions, " || "))
+	result += "}"
+	return result
+}
+
+func jsonName(entityName string) string {
+	return entityName + "JSON"
+}
+
+// hasJSONType checks if the JSON of a class (or of the variants of an union) doesn't match the class: the constructor
+// parses a field (`ts_transform`, bigint and `Uint8Array` values), the class has a `toJSON()` method, or the JSON of a
+// nested class doesn't match it. Type guards check the JSON, so they are declared with a separate `XJSON` type.
+// Interfaces and zod schemas describe the JSON itself.
+func (t *TypeScriptify) hasJSONType(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	if !t.CreateTypeGuards || t.CreateInterface || t.CreateZodSchema {
+		return false
+	}
+	if visited == nil {
+		visited = map[reflect.Type]bool{}
+	}
+	if union, isUnion := t.unions[typ]; isUnion {
+		for _, variant := range union.Variants {
+			if t.hasJSONType(variant.Type, visited) {
+				return true
+			}
+		}
+		return false
+	}
+	if typ.Kind() != reflect.Struct || visited[typ] || t.isManaged(typ) {
+		return false
+	}
+	visited[typ] = true
+
+	// All instantiations of a generic struct share the class (and its `toJSON()`):
+	generic := t.genericOf(typ)
+	instantiations := []reflect.Type{typ}
+	if generic != nil {
+		instantiations = generic.instantiations
+	}
+	for _, inst := range instantiations {
+		if t.needsSerialization(inst, map[reflect.Type]bool{}) {
+			return true
+		}
+	}
+	for n, field := range deepFields(typ) {
+		fieldTypes := []reflect.Type{field.Type}
+		if generic != nil {
+			fieldTypes = generic.fieldTypes(n)
+		}
+		if isInlineMap(field) {
+			if _, differs := t.jsonTypeExpr(nil, []reflect.Type{containerElem(field.Type)}, visited); differs {
+				return true
+			}
+			continue
+		}
+		isPtr := field.Type.Kind() == reflect.Ptr
+		if isPtr {
+			field.Type = field.Type.Elem()
+		}
+		if t.getJSONFieldName(field, isPtr) == "" {
+			continue
+		}
+		opts := t.getFieldOptions(typ, field)
+		if generic != nil && field.Tag.Get(tsType) == "" && field.Tag.Get(tsTransformTag) == "" {
+			if _, hasParams := t.genericTypeExpr(generic, fieldTypes); hasParams {
+				opts = TypeOptions{}
+			}
+		}
+		if _, differs := t.fieldJSONType(generic, fieldInfo{types: fieldTypes, opts: opts, quoted: t.quotedField(field)}, visited); differs {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldJSONType returns the TypeScript type of a field in the JSON, and if it differs from the type of the class field.
+// The JSON type of values parsed by a custom `TSTransform` isn't known.
+func (t *TypeScriptify) fieldJSONType(generic *genericStruct, fld fieldInfo, visited map[reflect.Type]bool) (string, bool) {
+	if fld.opts.TSTransform != "" {
+		jsonType := "unknown"
+		if fld.quoted || isByteSlice(fld.types[0]) {
+			jsonType = "string"
+		}
+		return jsonType, jsonType != fld.opts.TSType
+	}
+	if fld.opts.TSType != "" {
+		if t.isAliasField(fld.types[0], fld.opts) {
+			typ := fld.types[0]
+			if typ.Kind() == reflect.Ptr {
+				typ = typ.Elem()
+			}
+			return t.typeRef(typ), false
+		}
+		return fld.opts.TSType, false
+	}
+	return t.jsonTypeExpr(generic, fld.types, visited)
+}
+
+// jsonTypeExpr returns the TypeScript type of values in the JSON (see `hasJSONType()`), and if it differs from the type
+// of the class field. types contains the type in every instantiation of the generic struct g (or just one type if g is
+// nil).
+func (t *TypeScriptify) jsonTypeExpr(g *genericStruct, types []reflect.Type, visited map[reflect.Type]bool) (string, bool) {
+	if !t.CreateTypeGuards || t.CreateInterface || t.CreateZodSchema {
+		if g != nil {
+			tsType, _ := t.genericTypeExpr(g, types)
+			return tsType, false
+		}
+		return t.typeExpr(types[0]), false
+	}
+	if n := g.paramIndex(types); n >= 0 {
+		return g.params[n], false
+	}
+	typ := types[0]
+	if t.isPrimitiveAlias(typ) {
+		return t.typeRef(typ), false
+	}
+	if opts, found := t.typeOptions(typ); found && opts.TSType != "" {
+		if opts.TSTransform == "" {
+			return opts.TSType, false
+		}
+		jsonType := "unknown"
+		if _, isCustom := t.fieldTypeOptions[typ]; !isCustom {
+			// bigint and Uint8Array values are strings in the JSON:
+			jsonType = "string"
+		}
+		return jsonType, jsonType != opts.TSType
+	}
+	if tsType, _, is := t.marshalerType(typ); is {
+		return tsType, false
+	}
+	if _, isUnion := t.unions[typ]; isUnion {
+		if t.hasJSONType(typ, visited) {
+			return t.reference(jsonName(t.typeName(typ))), true
+		}
+		return t.typeRef(typ), false
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.jsonTypeExpr(g, mapTypes(types, reflect.Type.Elem), visited)
+	case reflect.Slice, reflect.Array:
+		elem, differs := t.jsonTypeExpr(g, mapTypes(types, reflect.Type.Elem), visited)
+		return elem + "[]", differs
+	case reflect.Map:
+		elem, differs := t.jsonTypeExpr(g, mapTypes(types, reflect.Type.Elem), visited)
+		return t.mapType(typ.Key(), elem), differs
+	case reflect.Struct:
+		if t.isInlineStruct(typ) {
+			var fields []string
+			differs := false
+			for _, fld := range t.structFields(typ) {
+				fieldType, fieldDiffers := t.fieldJSONType(nil, fld, visited)
+				differs = differs || fieldDiffers
+				name, optional := fld.name, ""
+				if strings.HasSuffix(name, "?") {
+					name, optional = strings.TrimSuffix(name, "?"), "?"
+				}
+				if fld.nullable {
+					fieldType += " | null"
+				}
+				fields = append(fields, tsPropertyName(name)+optional+": "+fieldType)
+			}
+			if !differs {
+				return t.typeExpr(typ), false
+			}
+			return "{ " + strings.Join(fields, "; ") + " }", true
+		}
+		name := t.structName(typ)
+		differs := t.hasJSONType(typ, visited)
+		if differs {
+			name = jsonName(name)
+		}
+		name = t.reference(name)
+		nested := t.genericOf(typ)
+		if nested == nil {
+			return name, differs
+		}
+		instArgs := make([][]reflect.Type, len(types))
+		for i := range types {
+			instArgs[i] = t.argTypes(types[i])
+		}
+		var args []string
+		for n := range nested.params {
+			argTypes := make([]reflect.Type, len(types))
+			known := true
+			for i := range types {
+				argTypes[i] = instArgs[i][n]
+				known = known && argTypes[i] != nil
+			}
+			if !known {
+				args = append(args, "any")
+				continue
+			}
+			arg, argDiffers := t.jsonTypeExpr(g, argTypes, visited)
+			args = append(args, arg)
+			differs = differs || argDiffers
+		}
+		return name + "<" + strings.Join(args, ", ") + ">", differs
+	}
+	if _, isEnum := t.enums[typ]; isEnum {
+		return t.typeRef(typ), false
+	}
+	if tsType, found := t.kinds[typ.Kind()]; found {
+		return tsType, false
+	}
+	return "any", false
+}
+
+// jsonInterface returns the interface describing the JSON of a class, checked by its type guard.
+func (t *TypeScriptify) jsonInterface(typeOf reflect.Type, entityName, typeParams string, generic *genericStruct, bases []reflect.Type, fields []fieldInfo) string {
+	var extends []string
+	for _, base := range bases {
+		baseType, _ := t.jsonTypeExpr(nil, []reflect.Type{base}, nil)
+		extends = append(extends, baseType)
+	}
+	result := fmt.Sprintf("interface %s%s", jsonName(entityName), typeParams)
+	if len(extends) > 0 {
+		result += " extends " + strings.Join(extends, ", ")
+	}
+	result += " {\n"
+	for _, fld := range fields {
+		fieldType, _ := t.fieldJSONType(generic, fld, nil)
+		name, optional := fld.name, ""
+		if strings.HasSuffix(name, "?") {
+			name, optional = strings.TrimSuffix(name, "?"), "?"
+		}
+		if fld.nullable {
+			fieldType += " | null"
+		}
+		result += fmt.Sprint(t.Indent, tsPropertyName(name), optional, ": ", fieldType, ";\n")
+	}
+	if _, hasInlineMap := inlineMapField(typeOf); hasInlineMap {
+		result += t.Indent + "[key: string]: any;\n"
+	}
+	result += "}"
+	if !t.DontExport {
+		result = "export " + result
+	}
+	return result
+}
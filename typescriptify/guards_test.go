@@ -0,0 +1,539 @@
+package typescriptify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTypeGuards(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Person{}).
+		WithInterface(true).
+		WithTypeGuards(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface Dummy {
+    something: string;
+}
+export function isDummy(x: unknown): x is Dummy {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["something"];
+}
+export interface Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+}
+export function isAddress(x: unknown): x is Address {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "number" === typeof o["duration"]
+        && (o["text"] === undefined || "string" === typeof o["text"])
+        && (o["Text2"] === undefined || "string" === typeof o["Text2"]);
+}
+export interface Person {
+    name: string;
+    nicknames: string[];
+    addresses: Address[];
+    address?: Address;
+    metadata: {[key:string]:string};
+    friends: Person[];
+    a: Dummy;
+}
+export function isPerson(x: unknown): x is Person {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"]
+        && (o["nicknames"] === null || Array.isArray(o["nicknames"]) && o["nicknames"].every((v1: unknown) => "string" === typeof v1))
+        && (o["addresses"] === null || Array.isArray(o["addresses"]) && o["addresses"].every((v1: unknown) => isAddress(v1)))
+        && (o["address"] === undefined || o["address"] === null || isAddress(o["address"]))
+        && o["metadata"] !== undefined
+        && (o["friends"] === null || Array.isArray(o["friends"]) && o["friends"].every((v1: unknown) => isPerson(v1)))
+        && isDummy(o["a"]);
+}`
+	person := `{"name": "Jane", "nicknames": ["J"], "addresses": [{"duration": 1}], "metadata": "{}", "friends": [], "a": {"something": "x"}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isPerson(` + person + `)`,
+		`!isPerson(null)`,
+		`!isPerson("Jane")`,
+		`!isPerson({...` + person + `, "name": 7})`,
+		`!isPerson({...` + person + `, "addresses": [{"duration": "1"}]})`,
+		`!isPerson({...` + person + `, "address": {"text": "no duration"}})`,
+		`isPerson({...` + person + `, "friends": [` + person + `]})`,
+		`!isPerson({...` + person + `, "friends": [{}]})`,
+		// Nil slices and pointers are encoded as null:
+		`isPerson(` + jsonizeOrPanic(Person{}) + `)`,
+	})
+}
+
+func TestTypeGuardsWithEnumsGenericsAndUnions(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddEnum(allWeekdaysV1).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"square": Square{}, "circle": Circle{}}).
+		Add(Holliday{}).
+		Add(Listings{}).
+		Add(Drawing{}).
+		WithInterface(true).
+		WithTypeGuards(true).
+		WithBackupDir("")
+
+	desiredResult := `export enum Weekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export function isWeekday(x: unknown): x is Weekday {
+    return ([Weekday.SUNDAY, Weekday.MONDAY, Weekday.TUESDAY, Weekday.WEDNESDAY, Weekday.THURSDAY, Weekday.FRIDAY, Weekday.SATURDAY] as unknown[]).includes(x);
+}
+export interface Circle {
+    kind: "circle";
+    radius: number;
+}
+export function isCircle(x: unknown): x is Circle {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return o["kind"] === "circle"
+        && "number" === typeof o["radius"];
+}
+export interface Square {
+    kind: "square";
+    side: number;
+}
+export function isSquare(x: unknown): x is Square {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return o["kind"] === "square"
+        && "number" === typeof o["side"];
+}
+export type Shape = Circle | Square;
+export function isShape(x: unknown): x is Shape {
+    return isCircle(x) || isSquare(x);
+}
+export interface Holliday {
+    name: string;
+    weekday: Weekday;
+}
+export function isHolliday(x: unknown): x is Holliday {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"]
+        && isWeekday(o["weekday"]);
+}
+export interface Result<T> {
+    value?: T;
+    error: string;
+}
+export function isResult<T>(x: unknown, isT: (x: unknown) => x is T): x is Result<T> {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return (o["value"] === undefined || o["value"] === null || isT(o["value"]))
+        && "string" === typeof o["error"];
+}
+export interface Dummy {
+    something: string;
+}
+export function isDummy(x: unknown): x is Dummy {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["something"];
+}
+export interface HasName {
+    name: string;
+}
+export function isHasName(x: unknown): x is HasName {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"];
+}
+export interface Page<T> {
+    items: T[];
+    total: number;
+}
+export function isPage<T>(x: unknown, isT: (x: unknown) => x is T): x is Page<T> {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return (o["items"] === null || Array.isArray(o["items"]) && o["items"].every((v1: unknown) => isT(v1)))
+        && "number" === typeof o["total"];
+}
+export interface Listings {
+    names: Page<HasName>;
+    dummies: Page<Dummy>;
+    result: Result<Page<HasName>>;
+    counts: Result<number>;
+}
+export function isListings(x: unknown): x is Listings {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return isPage(o["names"], (v1: unknown): v1 is HasName => isHasName(v1))
+        && isPage(o["dummies"], (v1: unknown): v1 is Dummy => isDummy(v1))
+        && isResult(o["result"], (v1: unknown): v1 is Page<HasName> => isPage(v1, (v2: unknown): v2 is HasName => isHasName(v2)))
+        && isResult(o["counts"], (v1: unknown): v1 is number => "number" === typeof v1);
+}
+export interface Drawing {
+    main: Shape;
+    shapes: Shape[];
+    named: {[key: string]: Shape};
+}
+export function isDrawing(x: unknown): x is Drawing {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return isShape(o["main"])
+        && (o["shapes"] === null || Array.isArray(o["shapes"]) && o["shapes"].every((v1: unknown) => isShape(v1)))
+        && (o["named"] === null || "object" === typeof o["named"] && o["named"] !== null && !Array.isArray(o["named"]) && Object.values(o["named"]).every((v1: unknown) => isShape(v1)));
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isHolliday({"name": "x", "weekday": 1})`,
+		`!isHolliday({"name": "x", "weekday": 7})`,
+		`isListings({"names": {"items": [{"name": "a"}], "total": 1}, "dummies": {"items": [], "total": 0}, "result": {"error": ""}, "counts": {"value": 1, "error": ""}})`,
+		`!isListings({"names": {"items": [{"name": 1}], "total": 1}, "dummies": {"items": [], "total": 0}, "result": {"error": ""}, "counts": {"value": 1, "error": ""}})`,
+		`isDrawing({"main": {"kind": "circle", "radius": 1}, "shapes": [{"kind": "square", "side": 1}], "named": {}})`,
+		`!isDrawing({"main": {"kind": "circle", "side": 1}, "shapes": [], "named": {}})`,
+	})
+}
+
+type GuardedCounter struct {
+	Count   int    `json:"count,string"`
+	Enabled *bool  `json:"enabled,string,omitempty"`
+	Data    []byte `json:"data"`
+}
+
+func TestTypeGuardsCheckTheJSON(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(GuardedCounter{}).
+		WithUint8Array(true).
+		WithTypeGuards(true).
+		WithBackupDir("")
+
+	// `,string` values and base64 byte slices are strings in the JSON (even if the class converts them):
+	desiredResult := `export class GuardedCounter {
+    count: string;
+    enabled?: string;
+    data: Uint8Array;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.count = source["count"];
+        this.enabled = source["enabled"];
+        this.data = source["data"] == null ? source["data"] : Uint8Array.from(atob(source["data"]), (c) => c.charCodeAt(0));
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "data": this.data == null ? this.data : btoa(Array.from(this.data, (b) => String.fromCharCode(b)).join("")),
+        };
+    }
+}
+export interface GuardedCounterJSON {
+    count: string;
+    enabled?: string;
+    data: string;
+}
+export function isGuardedCounter(x: unknown): x is GuardedCounterJSON {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["count"]
+        && (o["enabled"] === undefined || "string" === typeof o["enabled"])
+        && (o["data"] === null || "string" === typeof o["data"]);
+}`
+	enabled := true
+	testConverter(t, converter, true, desiredResult, []string{
+		`isGuardedCounter(` + jsonizeOrPanic(GuardedCounter{Count: 7, Enabled: &enabled, Data: []byte("abc")}) + `)`,
+		`isGuardedCounter({"count": 7, "data": "YWJj"}) === false`,
+		`isGuardedCounter({"count": "7", "enabled": true, "data": "YWJj"}) === false`,
+		`isGuardedCounter({"count": "7", "data": [97, 98, 99]}) === false`,
+	})
+}
+
+type Meeting struct {
+	Kind  string    `json:"kind"`
+	Start time.Time `json:"start"`
+}
+
+func (Meeting) Area() float64 { return 0 }
+
+type Agenda struct {
+	Calendar Calendar      `json:"calendar"`
+	Shapes   []Shape       `json:"shapes"`
+	Events   Page[Event]   `json:"events"`
+	Names    Page[HasName] `json:"names"`
+}
+
+func TestTypeGuardsOfClassesWithParsedFields(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"square": Square{}, "meeting": Meeting{}}).
+		Add(Agenda{}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)", TSSerialize: "__VALUE__.toISOString()"}).
+		WithTypeGuards(true).
+		WithBackupDir("")
+
+	// The guards check the JSON, not the classes (with Date values and toJSON() methods):
+	desiredResult := `export class Meeting {
+    kind: "meeting";
+    start: Date;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.kind = source["kind"];
+        this.start = new Date(source["start"]);
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "start": this.start.toISOString(),
+        };
+    }
+}
+export interface MeetingJSON {
+    kind: "meeting";
+    start: unknown;
+}
+export function isMeeting(x: unknown): x is MeetingJSON {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return o["kind"] === "meeting"
+        && o["start"] !== undefined;
+}
+export class Square {
+    kind: "square";
+    side: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.kind = source["kind"];
+        this.side = source["side"];
+    }
+}
+export function isSquare(x: unknown): x is Square {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return o["kind"] === "square"
+        && "number" === typeof o["side"];
+}
+export type Shape = Meeting | Square;
+export const Shape = {
+    discriminator: "kind",
+    variants: {
+        "meeting": Meeting,
+        "square": Square,
+    } as {[key: string]: any},
+};
+export type ShapeJSON = MeetingJSON | Square;
+export function isShape(x: unknown): x is ShapeJSON {
+    return isMeeting(x) || isSquare(x);
+}
+export class HasName {
+    name: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+    }
+}
+export function isHasName(x: unknown): x is HasName {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"];
+}
+export class Page<T> {
+    items: T[];
+    total: number;
+
+    constructor(source: any = {}, convertT: (v: any) => any = (v) => v) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.items = ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => convertT(e1)))(source["items"]);
+        this.total = source["total"];
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "items": this.serializeValues(this.items),
+        };
+    }
+
+	` + tsSerializeValuesFunc + `
+}
+export interface PageJSON<T> {
+    items: T[];
+    total: number;
+}
+export function isPage<T>(x: unknown, isT: (x: unknown) => x is T): x is PageJSON<T> {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return (o["items"] === null || Array.isArray(o["items"]) && o["items"].every((v1: unknown) => isT(v1)))
+        && "number" === typeof o["total"];
+}
+export class Event {
+    name: string;
+    start: Date;
+    end?: Date;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.start = new Date(source["start"]);
+        this.end = new Date(source["end"]);
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "start": this.start.toISOString(),
+            "end": this.end == null ? this.end : this.end.toISOString(),
+        };
+    }
+}
+export interface EventJSON {
+    name: string;
+    start: unknown;
+    end?: unknown;
+}
+export function isEvent(x: unknown): x is EventJSON {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"]
+        && o["start"] !== undefined;
+}
+export class Calendar {
+    title: string;
+    main: Event;
+    events: Event[];
+    by_name: {[key: string]: Event};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.title = source["title"];
+        this.main = this.convertValues(source["main"], Event);
+        this.events = this.convertValues(source["events"], Event);
+        this.by_name = this.convertValues(source["by_name"], Event, true);
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "main": this.serializeValues(this.main),
+            "events": this.serializeValues(this.events),
+            "by_name": this.serializeValues(this.by_name),
+        };
+    }
+
+	` + tsConvertValuesFunc + `
+
+	` + tsSerializeValuesFunc + `
+}
+export interface CalendarJSON {
+    title: string;
+    main: EventJSON;
+    events: EventJSON[];
+    by_name: {[key: string]: EventJSON};
+}
+export function isCalendar(x: unknown): x is CalendarJSON {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["title"]
+        && isEvent(o["main"])
+        && (o["events"] === null || Array.isArray(o["events"]) && o["events"].every((v1: unknown) => isEvent(v1)))
+        && (o["by_name"] === null || "object" === typeof o["by_name"] && o["by_name"] !== null && !Array.isArray(o["by_name"]) && Object.values(o["by_name"]).every((v1: unknown) => isEvent(v1)));
+}
+export class Agenda {
+    calendar: Calendar;
+    shapes: Shape[];
+    events: Page<Event>;
+    names: Page<HasName>;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.calendar = this.convertValues(source["calendar"], Calendar);
+        this.shapes = this.convertValues(source["shapes"], Shape);
+        this.events = this.convertValues(source["events"], Page, false, (v1: any) => this.convertValues(v1, Event));
+        this.names = this.convertValues(source["names"], Page, false, (v1: any) => this.convertValues(v1, HasName));
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "calendar": this.serializeValues(this.calendar),
+            "shapes": this.serializeValues(this.shapes),
+            "events": this.serializeValues(this.events),
+        };
+    }
+
+	` + tsConvertValuesFunc + `
+
+	` + tsSerializeValuesFunc + `
+}
+export interface AgendaJSON {
+    calendar: CalendarJSON;
+    shapes: ShapeJSON[];
+    events: PageJSON<EventJSON>;
+    names: PageJSON<HasName>;
+}
+export function isAgenda(x: unknown): x is AgendaJSON {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return isCalendar(o["calendar"])
+        && (o["shapes"] === null || Array.isArray(o["shapes"]) && o["shapes"].every((v1: unknown) => isShape(v1)))
+        && isPage(o["events"], (v1: unknown): v1 is EventJSON => isEvent(v1))
+        && isPage(o["names"], (v1: unknown): v1 is HasName => isHasName(v1));
+}`
+	event := `{"name": "a", "start": "2021-01-02T03:04:05.000Z", "end": "2021-01-03T03:04:05.000Z"}`
+	jsn := `{"calendar": {"title": "c", "main": ` + event + `, "events": [` + event + `], "by_name": {"a": ` + event + `}}, "shapes": [{"kind": "meeting", "start": "2021-01-02T03:04:05.000Z"}, {"kind": "square", "side": 1}], "events": {"items": [` + event + `], "total": 1}, "names": {"items": [{"name": "b"}], "total": 1}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isAgenda(` + jsn + `)`,
+		`isAgenda(JSON.parse(JSON.stringify(new Agenda(` + jsn + `))))`,
+		`!isAgenda({...` + jsn + `, "shapes": [{"kind": "meeting"}]})`,
+		`((x: unknown) => isEvent(x) && x.start === "2021-01-02T03:04:05.000Z")(` + event + `)`,
+		`((x: unknown) => isAgenda(x) && x.events.items[0].start === "2021-01-02T03:04:05.000Z")(` + jsn + `)`,
+	})
+}
@@ -0,0 +1,185 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// maxSafeInteger is the largest integer JavaScript numbers represent exactly (`Number.MAX_SAFE_INTEGER`).
+const maxSafeInteger = 1<<53 - 1
+
+// Int64Mode defines how 64-bit integers (which can be bigger than `Number.MAX_SAFE_INTEGER`) are converted.
+type Int64Mode int
+
+const (
+	// Int64AsNumber converts 64-bit integers to `number`, fields with the `,string` option to `string` (default).
+	Int64AsNumber Int64Mode = iota
+	// Int64AsString converts all 64-bit integers to `string`, the fields need the `,string` option.
+	Int64AsString
+	// Int64AsBigInt converts 64-bit integers to `bigint`, class constructors (or zod schemas) parse them with `BigInt()`.
+	Int64AsBigInt
+)
+
+// WithInt64Mode sets how `int64` and `uint64` values are converted.
+//
+// `Int64AsBigInt` can't be used with interfaces (without zod schemas), they describe the JSON itself where nothing
+// parses the values with `BigInt()`. The conversion fails, use `Int64AsString` instead.
+func (t *TypeScriptify) WithInt64Mode(mode Int64Mode) *TypeScriptify {
+	t.Int64Mode = mode
+	return t
+}
+
+func is64Bit(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Int64 || typ.Kind() == reflect.Uint64
+}
+
+// int64TypeOptions returns the builtin options for 64-bit integers in the string and bigint modes. Enums and types
+// with a custom JSON encoding are converted as usual.
+func (t *TypeScriptify) int64TypeOptions(typ reflect.Type) (TypeOptions, bool) {
+	if !is64Bit(typ) || t.Int64Mode == Int64AsNumber {
+		return TypeOptions{}, false
+	}
+	if _, isEnum := t.enums[typ]; isEnum {
+		return TypeOptions{}, false
+	}
+	ptr := reflect.PtrTo(typ)
+	if ptr.Implements(jsonMarshalerType) || ptr.Implements(textMarshalerType) {
+		return TypeOptions{}, false
+	}
+	if t.Int64Mode == Int64AsString {
+		return TypeOptions{
+			TSType:      "string",
+			TSTransform: nullGuard + "String(__VALUE__)",
+			TSZod:       "z.coerce.string()",
+		}, true
+	}
+	return TypeOptions{
+		TSType:      "bigint",
+		TSTransform: nullGuard + "BigInt(__VALUE__)",
+		// JSON.stringify() fails on bigint values, the fields have the `,string` option (see `checkInt64Field()`):
+		TSSerialize: nullGuard + "__VALUE__.toString()",
+		TSZod:       "z.coerce.bigint()",
+	}, true
+}
+
+// isQuotedEnum checks if an enum has values JavaScript numbers can't represent exactly. With `Int64AsString` and
+// `Int64AsBigInt` its members are strings, and its fields need the `,string` option.
+func (t *TypeScriptify) isQuotedEnum(typ reflect.Type) bool {
+	if typ.Name() == "" && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if t.Int64Mode == Int64AsNumber {
+		return false
+	}
+	for _, el := range t.enums[typ] {
+		if !isSafeInteger(el.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// quotedField checks if a field is typed as a string because of the `,string` option, fields of quoted enums keep
+// their enum type.
+func (t *TypeScriptify) quotedField(field reflect.StructField) bool {
+	return parseJSONTag(field.Tag.Get("json")).quotesValue(field.Type) && !t.isQuotedEnum(field.Type)
+}
+
+// isSafeInteger checks if an integer enum value is represented exactly by a JavaScript number.
+func isSafeInteger(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() >= -maxSafeInteger && v.Int() <= maxSafeInteger
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() <= maxSafeInteger
+	}
+	return true
+}
+
+// checkInt64Field warns if a field (or a slice/map in it) is a 64-bit integer converted to `number`. Converted to
+// `string` or `bigint`, the field must have the `,string` option: JSON numbers above 2^53 lose precision before
+// `String()` or `BigInt()` parses them, type guards expect strings, and `toJSON()` encodes bigint values as strings.
+// Fields of enums with string members (see `isQuotedEnum()`) need the option too. The option doesn't apply to slices
+// and maps, their 64-bit integers are converted to `number` (see `int64Numbers()`) with a warning.
+func (t *TypeScriptify) checkInt64Field(typeOf reflect.Type, field reflect.StructField, opts TypeOptions) error {
+	elem := containerElem(field.Type)
+	quoted := parseJSONTag(field.Tag.Get("json")).quotesValue(field.Type)
+	if _, isEnum := t.enums[elem]; isEnum {
+		if t.isQuotedEnum(elem) && !quoted {
+			return fmt.Errorf("%s.%s: enum %s has values above 2^53, it needs the `,string` option", typeOf.Name(), field.Name, t.typeName(elem))
+		}
+		return nil
+	}
+	if !is64Bit(elem) {
+		return nil
+	}
+	if _, isCustom := t.fieldTypeOptions[elem]; isCustom {
+		return nil
+	}
+	if _, isMarshaler := t.marshalers[elem]; isMarshaler {
+		return nil
+	}
+	switch t.Int64Mode {
+	case Int64AsNumber:
+		if opts.TSType == "" {
+			t.warnf("%s.%s is a 64-bit integer converted to number, values above 2^53 lose precision (use the `,string` option or WithInt64Mode())", typeOf.Name(), field.Name)
+		}
+	case Int64AsString, Int64AsBigInt:
+		builtin, _ := t.builtinTypeOptions(field.Type)
+		if opts.TSType == builtin.TSType && !quoted {
+			mode := "Int64AsString"
+			if t.Int64Mode == Int64AsBigInt {
+				mode = "Int64AsBigInt"
+			}
+			if elem != field.Type {
+				t.warnf("%s.%s: the 64-bit integers of slices and maps are converted to number with %s (the `,string` option doesn't apply to them), values above 2^53 lose precision", typeOf.Name(), field.Name, mode)
+				return nil
+			}
+			return fmt.Errorf("%s.%s: %s needs the `,string` option, values above 2^53 lose precision as JSON numbers", typeOf.Name(), field.Name, mode)
+		}
+	}
+	return nil
+}
+
+// int64Numbers returns the type of a slice, array or map (or a pointer to it) with its 64-bit integers replaced by
+// float64, so that they are converted to `number` in every Int64Mode. Types without 64-bit integers are unchanged.
+func (t *TypeScriptify) int64Numbers(typ reflect.Type) reflect.Type {
+	if _, isCustom := t.fieldTypeOptions[typ]; isCustom {
+		return typ
+	}
+	if _, is := t.int64TypeOptions(typ); is {
+		return reflect.TypeOf(float64(0))
+	}
+	if _, isMarshaler := t.marshalers[typ]; isMarshaler {
+		return typ
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		if elem := t.int64Numbers(typ.Elem()); elem != typ.Elem() {
+			return reflect.PtrTo(elem)
+		}
+	case reflect.Slice:
+		if elem := t.int64Numbers(typ.Elem()); elem != typ.Elem() {
+			return reflect.SliceOf(elem)
+		}
+	case reflect.Array:
+		if elem := t.int64Numbers(typ.Elem()); elem != typ.Elem() {
+			return reflect.ArrayOf(typ.Len(), elem)
+		}
+	case reflect.Map:
+		if elem := t.int64Numbers(typ.Elem()); elem != typ.Elem() {
+			return reflect.MapOf(typ.Key(), elem)
+		}
+	}
+	return typ
+}
+
+// Warnings returns the warnings of the last conversion, like 64-bit integers which lose precision as numbers.
+func (t *TypeScriptify) Warnings() []string {
+	return t.warnings
+}
+
+func (t *TypeScriptify) warnf(format string, args ...interface{}) {
+	t.warnings = append(t.warnings, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,321 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Snowflake struct {
+	ID      int64    `json:"id"`
+	Quoted  int64    `json:"quoted,string"`
+	Parents []uint64 `json:"parents"`
+	Count   int32    `json:"count"`
+}
+
+type Permission uint64
+
+const (
+	PermissionRead  Permission = 1
+	PermissionAdmin Permission = 1 << 62
+)
+
+var allPermissions = []struct {
+	Value  Permission
+	TSName string
+}{
+	{PermissionRead, "READ"},
+	{PermissionAdmin, "ADMIN"},
+}
+
+func TestInt64AsNumber(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Snowflake{}).WithInterface(true).WithBackupDir("")
+	desiredResult := `export interface Snowflake {
+    id: number;
+    quoted: string;
+    parents: number[];
+    count: number;
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`((s: Snowflake) => s.quoted)({id: 1, quoted: "9007199254740993", parents: [], count: 0}) === "9007199254740993"`,
+	})
+	assert.Equal(t, []string{
+		"Snowflake.ID is a 64-bit integer converted to number, values above 2^53 lose precision (use the `,string` option or WithInt64Mode())",
+		"Snowflake.Parents is a 64-bit integer converted to number, values above 2^53 lose precision (use the `,string` option or WithInt64Mode())",
+	}, converter.Warnings())
+
+	// Enum members can't be bigint values:
+	_, err := New().AddEnum(allPermissions).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "enum Permission: ADMIN is 4611686018427387904, JavaScript numbers can't represent it exactly (use WithInt64Mode(Int64AsString) and the `,string` option)")
+}
+
+type Role struct {
+	Permission Permission  `json:"permission,string"`
+	Inherited  *Permission `json:"inherited,string,omitempty"`
+}
+
+func TestInt64EnumAsString(t *testing.T) {
+	t.Parallel()
+
+	// Enums with values above 2^53 have string members, in the string and bigint modes:
+	for _, mode := range []Int64Mode{Int64AsString, Int64AsBigInt} {
+		converter := New().AddEnum(allPermissions).Add(Role{}).WithInt64Mode(mode).WithTypeGuards(true).WithBackupDir("")
+		desiredResult := `export enum Permission {
+    READ = "1",
+    ADMIN = "4611686018427387904",
+}
+export function isPermission(x: unknown): x is Permission {
+    return ([Permission.READ, Permission.ADMIN] as unknown[]).includes(x);
+}
+export class Role {
+    permission: Permission;
+    inherited?: Permission;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.permission = source["permission"];
+        this.inherited = source["inherited"];
+    }
+}
+export function isRole(x: unknown): x is Role {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return isPermission(o["permission"])
+        && (o["inherited"] === undefined || isPermission(o["inherited"]));
+}`
+		testConverter(t, converter, true, desiredResult, []string{
+			`new Role({"permission": "4611686018427387904"}).permission === Permission.ADMIN`,
+			`isRole({"permission": "4611686018427387904"})`,
+			`!isRole({"permission": 4611686018427387904})`,
+		})
+	}
+
+	schema, err := New().AddEnum(allPermissions).Add(Role{}).WithInt64Mode(Int64AsString).ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "Permission": {"enum": ["1", "4611686018427387904"]},
+        "Role": {
+            "type": "object",
+            "properties": {
+                "permission": {"$ref": "#/$defs/Permission"},
+                "inherited": {"$ref": "#/$defs/Permission"}
+            },
+            "required": ["permission"]
+        }
+    }
+}`, schema)
+
+	// Go encodes the values as JSON numbers without the `,string` option:
+	type Member struct {
+		Permissions []Permission `json:"permissions"`
+	}
+	_, err = New().AddEnum(allPermissions).Add(Member{}).WithInt64Mode(Int64AsString).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "Member.Permissions: enum Permission has values above 2^53, it needs the `,string` option")
+}
+
+type BigSnowflake struct {
+	ID     int64   `json:"id,string"`
+	Parent *uint64 `json:"parent,string,omitempty"`
+	Count  int32   `json:"count"`
+}
+
+func TestInt64AsString(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(BigSnowflake{}).WithInt64Mode(Int64AsString).WithTypeGuards(true).WithBackupDir("")
+	desiredResult := `export class BigSnowflake {
+    id: string;
+    parent?: string;
+    count: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.id = source["id"] == null ? source["id"] : String(source["id"]);
+        this.parent = source["parent"] == null ? source["parent"] : String(source["parent"]);
+        this.count = source["count"];
+    }
+}
+export function isBigSnowflake(x: unknown): x is BigSnowflake {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["id"]
+        && (o["parent"] === undefined || "string" === typeof o["parent"])
+        && "number" === typeof o["count"];
+}`
+	jsn := jsonizeOrPanic(BigSnowflake{ID: 1<<53 + 1, Count: 1})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new BigSnowflake(` + jsn + `).id === "9007199254740993"`,
+		`new BigSnowflake(` + jsn + `).parent === undefined`,
+		`isBigSnowflake(` + jsn + `)`,
+	})
+	assert.Empty(t, converter.Warnings())
+}
+
+func TestInt64AsStringWithoutStringOption(t *testing.T) {
+	t.Parallel()
+
+	// Go encodes the values as JSON numbers, the type guard would reject them:
+	_, err := New().Add(Snowflake{}).WithInt64Mode(Int64AsString).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "Snowflake.ID: Int64AsString needs the `,string` option, values above 2^53 lose precision as JSON numbers")
+}
+
+func TestInt64AsBigInt(t *testing.T) {
+	t.Parallel()
+
+	// BigInt() needs the ES2020 lib, so the code isn't compiled with the default tsc settings here:
+	converter := New().Add(BigSnowflake{}).WithInt64Mode(Int64AsBigInt).WithTypeGuards(true).WithBackupDir("")
+	desiredResult := `export class BigSnowflake {
+    id: bigint;
+    parent?: bigint;
+    count: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.id = source["id"] == null ? source["id"] : BigInt(source["id"]);
+        this.parent = source["parent"] == null ? source["parent"] : BigInt(source["parent"]);
+        this.count = source["count"];
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "id": this.id == null ? this.id : this.id.toString(),
+            "parent": this.parent == null ? this.parent : this.parent.toString(),
+        };
+    }
+}
+export interface BigSnowflakeJSON {
+    id: string;
+    parent?: string;
+    count: number;
+}
+export function isBigSnowflake(x: unknown): x is BigSnowflakeJSON {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["id"]
+        && (o["parent"] === undefined || "string" === typeof o["parent"])
+        && "number" === typeof o["count"];
+}`
+	testConvertedCode(t, converter, desiredResult)
+
+	converter = New().Add(BigSnowflake{}).WithInt64Mode(Int64AsBigInt).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export const BigSnowflakeSchema = z.object({
+    id: z.coerce.bigint(),
+    parent: z.coerce.bigint().optional(),
+    count: z.number(),
+});
+export type BigSnowflake = z.infer<typeof BigSnowflakeSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestInt64AsBigIntWithoutStringOption(t *testing.T) {
+	t.Parallel()
+
+	// JSON numbers are parsed (and rounded) before BigInt():
+	_, err := New().Add(Snowflake{}).WithInt64Mode(Int64AsBigInt).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "Snowflake.ID: Int64AsBigInt needs the `,string` option, values above 2^53 lose precision as JSON numbers")
+}
+
+type Ledger struct {
+	ID      int64            `json:"id,string"`
+	Parents []uint64         `json:"parents,string"`
+	Totals  map[string]int64 `json:"totals"`
+}
+
+func TestInt64SlicesAndMaps(t *testing.T) {
+	t.Parallel()
+
+	// The `,string` option doesn't apply to slices and maps, their values are JSON numbers:
+	converter := New().Add(Ledger{}).WithInt64Mode(Int64AsString).WithTypeGuards(true).WithBackupDir("")
+	desiredResult := `export class Ledger {
+    id: string;
+    parents: number[];
+    totals: {[key: string]: number};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.id = source["id"] == null ? source["id"] : String(source["id"]);
+        this.parents = source["parents"];
+        this.totals = source["totals"];
+    }
+}
+export function isLedger(x: unknown): x is Ledger {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["id"]
+        && (o["parents"] === null || Array.isArray(o["parents"]) && o["parents"].every((v1: unknown) => "number" === typeof v1))
+        && (o["totals"] === null || "object" === typeof o["totals"] && o["totals"] !== null && !Array.isArray(o["totals"]) && Object.values(o["totals"]).every((v1: unknown) => "number" === typeof v1));
+}`
+	jsn := jsonizeOrPanic(Ledger{ID: 1<<53 + 1, Parents: []uint64{1, 2}, Totals: map[string]int64{"a": 3}})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Ledger(` + jsn + `).id === "9007199254740993"`,
+		`new Ledger(` + jsn + `).parents[1] === 2`,
+		`new Ledger(` + jsn + `).totals["a"] === 3`,
+		`isLedger(` + jsn + `)`,
+	})
+	assert.Equal(t, []string{
+		"Ledger.Parents: the 64-bit integers of slices and maps are converted to number with Int64AsString (the `,string` option doesn't apply to them), values above 2^53 lose precision",
+		"Ledger.Totals: the 64-bit integers of slices and maps are converted to number with Int64AsString (the `,string` option doesn't apply to them), values above 2^53 lose precision",
+	}, converter.Warnings())
+
+	// Only the values of the `,string` fields are serialized as strings:
+	converter = New().Add(Ledger{}).WithInt64Mode(Int64AsBigInt).WithBackupDir("")
+	desiredResult = `export class Ledger {
+    id: bigint;
+    parents: number[];
+    totals: {[key: string]: number};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.id = source["id"] == null ? source["id"] : BigInt(source["id"]);
+        this.parents = source["parents"];
+        this.totals = source["totals"];
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "id": this.id == null ? this.id : this.id.toString(),
+        };
+    }
+}`
+	testConvertedCode(t, converter, desiredResult)
+	assert.Len(t, converter.Warnings(), 2)
+
+	converter = New().Add(Ledger{}).WithInt64Mode(Int64AsBigInt).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export const LedgerSchema = z.object({
+    id: z.coerce.bigint(),
+    parents: z.array(z.number()),
+    totals: z.record(z.string(), z.number()),
+});
+export type Ledger = z.infer<typeof LedgerSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestInt64AsBigIntWithInterfaces(t *testing.T) {
+	t.Parallel()
+
+	// Interfaces describe the JSON, where the values aren't bigint:
+	_, err := New().Add(BigSnowflake{}).WithInt64Mode(Int64AsBigInt).WithInterface(true).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "Int64AsBigInt can't be used with interfaces, nothing parses their JSON with BigInt() (use Int64AsString)")
+
+	// ...but zod schemas do:
+	_, err = New().Add(BigSnowflake{}).WithInt64Mode(Int64AsBigInt).WithInterface(true).WithZodSchema(true).WithBackupDir("").Convert(nil)
+	assert.Nil(t, err)
+}
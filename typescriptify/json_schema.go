@@ -0,0 +1,281 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var jsonSchemaDefNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// ConvertJSONSchema converts the registered structs and enums into a JSON Schema (draft 2020-12) document with one
+// `$defs` entry for every struct and enum.
+//
+// Field names, optional fields and descriptions follow the same rules as the TypeScript code (json tags,
+// `omitempty`, pointers, `ts_doc`).
+func (t *TypeScriptify) ConvertJSONSchema() (string, error) {
+	if err := t.collectEnumProviders(); err != nil {
+		return "", err
+	}
+	t.collectGenerics()
+	if err := t.collectMarshalers(); err != nil {
+		return "", err
+	}
+	if err := t.resolveNames(false); err != nil {
+		return "", err
+	}
+
+	defs := map[string]interface{}{}
+	for _, enumTyp := range t.enumTypes {
+		if _, err := t.jsonSchema(enumTyp.Type, defs); err != nil {
+			return "", err
+		}
+	}
+	for _, unionTyp := range t.unionTypes {
+		if _, err := t.jsonSchema(unionTyp, defs); err != nil {
+			return "", err
+		}
+	}
+	for _, strctTyp := range t.structTypes {
+		if _, err := t.jsonSchema(strctTyp.Type, defs); err != nil {
+			return "", err
+		}
+	}
+
+	byts, err := json.MarshalIndent(map[string]interface{}{
+		"$schema": jsonSchemaDraft,
+		"$defs":   defs,
+	}, "", t.Indent)
+	if err != nil {
+		return "", err
+	}
+	return string(byts), nil
+}
+
+// ConvertJSONSchemaToFile writes the JSON Schema to a file, see `ConvertJSONSchema()`.
+func (t *TypeScriptify) ConvertJSONSchemaToFile(fileName string) error {
+	if len(t.BackupDir) > 0 {
+		err := t.backup(fileName)
+		if err != nil {
+			return err
+		}
+	}
+
+	converted, err := t.ConvertJSONSchema()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, []byte(converted+"\n"), os.FileMode(0644))
+}
+
+// jsonSchemaDefName returns the `$defs` name for a struct/enum/union (generic instantiations get a name
+// with their type arguments).
+func (t *TypeScriptify) jsonSchemaDefName(typ reflect.Type) string {
+	if t.genericOf(typ) != nil {
+		tsType, _, _ := t.structRef(typ)
+		return strings.Trim(jsonSchemaDefNameRegexp.ReplaceAllString(tsType, "_"), "_")
+	}
+	return t.typeName(typ)
+}
+
+func jsonSchemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/$defs/" + name}
+}
+
+func (t *TypeScriptify) jsonSchema(typ reflect.Type, defs map[string]interface{}) (map[string]interface{}, error) {
+	if elements, isEnum := t.enums[typ]; isEnum {
+		name := t.jsonSchemaDefName(typ)
+		if _, found := defs[name]; !found {
+			var values []interface{}
+			for _, el := range elements {
+				if t.isQuotedEnum(typ) {
+					values = append(values, fmt.Sprint(el.value))
+				} else {
+					values = append(values, el.value)
+				}
+			}
+			defs[name] = map[string]interface{}{"enum": values}
+			if t.flagEnums[typ] {
+				// Any combination of the flags:
+				mask, err := t.flagsMask(name, elements)
+				if err != nil {
+					return nil, err
+				}
+				defs[name] = map[string]interface{}{"type": "integer", "minimum": 0, "maximum": mask}
+			}
+		}
+		return jsonSchemaRef(name), nil
+	}
+	if union, isUnion := t.unions[typ]; isUnion {
+		name := t.jsonSchemaDefName(typ)
+		if _, found := defs[name]; !found {
+			defs[name] = nil
+			var variants []interface{}
+			for _, variant := range union.Variants {
+				variantSchema, err := t.jsonSchema(variant.Type, defs)
+				if err != nil {
+					return nil, err
+				}
+				variants = append(variants, variantSchema)
+			}
+			defs[name] = map[string]interface{}{"oneOf": variants}
+		}
+		return jsonSchemaRef(name), nil
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	}
+	if typ == rawMessageType {
+		return map[string]interface{}{}, nil
+	}
+	if isByteSlice(typ) {
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+	}
+	if kind, is := t.marshalers[typ]; is {
+		return map[string]interface{}{"type": kind}, nil
+	}
+	if _, isManaged := t.fieldTypeOptions[typ]; isManaged {
+		// ManageType() changes only the TypeScript type, the JSON is still encoded by the custom marshaler (if any):
+		ptr := reflect.PtrTo(typ)
+		if ptr.Implements(jsonMarshalerType) {
+			kind, err := inferJSONKind(typ)
+			if err != nil {
+				// Unknown JSON, any value is valid
+				return map[string]interface{}{}, nil
+			}
+			return map[string]interface{}{"type": kind}, nil
+		}
+		if ptr.Implements(textMarshalerType) {
+			return map[string]interface{}{"type": "string"}, nil
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.jsonSchema(typ.Elem(), defs)
+	case reflect.Slice, reflect.Array:
+		items, err := t.jsonSchema(typ.Elem(), defs)
+		if err != nil {
+			return nil, err
+		}
+		schema := map[string]interface{}{"type": "array", "items": items}
+		if typ.Kind() == reflect.Array {
+			schema["minItems"] = typ.Len()
+			schema["maxItems"] = typ.Len()
+		}
+		return schema, nil
+	case reflect.Map:
+		values, err := t.jsonSchema(typ.Elem(), defs)
+		if err != nil {
+			return nil, err
+		}
+		schema := map[string]interface{}{"type": "object", "additionalProperties": values}
+		keys, err := t.mapKeyJSONSchema(typ.Key(), defs)
+		if err != nil {
+			return nil, err
+		}
+		if keys != nil {
+			schema["propertyNames"] = keys
+		}
+		return schema, nil
+	case reflect.Struct:
+		if t.isInlineStruct(typ) {
+			return t.jsonSchemaObject(typ, defs)
+		}
+		name := t.jsonSchemaDefName(typ)
+		if _, found := defs[name]; !found {
+			defs[name] = nil // Placeholder, for recursive structs
+			schema, err := t.jsonSchemaObject(typ, defs)
+			if err != nil {
+				return nil, err
+			}
+			defs[name] = schema
+		}
+		return jsonSchemaRef(name), nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	}
+
+	return nil, fmt.Errorf("cannot find JSON schema type for %s (%s)", typ.Kind().String(), typ.String())
+}
+
+func (t *TypeScriptify) jsonSchemaObject(typeOf reflect.Type, defs map[string]interface{}) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, field := range deepFields(typeOf) {
+		isPtr := field.Type.Kind() == reflect.Ptr
+		jsonFieldName := t.getJSONFieldName(field, isPtr)
+		if len(jsonFieldName) == 0 {
+			continue
+		}
+		name := strings.ReplaceAll(jsonFieldName, "?", "")
+
+		var schema map[string]interface{}
+		if discriminator, is := t.discriminatorValue(typeOf, name); is {
+			schema = map[string]interface{}{"const": discriminator}
+		} else if _, _, formatSchema, is := formatType(field.Type, parseJSONTag(field.Tag.Get("json")).value("format")); is {
+			schema = formatSchema
+		} else if _, _, is := t.marshalerType(field.Type); !is && t.quotedField(field) {
+			schema = map[string]interface{}{"type": "string"}
+		} else {
+			var err error
+			schema, err = t.jsonSchema(field.Type, defs)
+			if err != nil {
+				return nil, err
+			}
+		}
+		stripped := field
+		if isPtr {
+			stripped.Type = field.Type.Elem()
+		}
+		if len(schema) > 0 && t.canBeNull(stripped, isPtr) {
+			// The schema describes the JSON, where nil values are `null` whatever the nullability mode (an empty schema
+			// already accepts `null`):
+			schema = map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+		}
+		if doc := t.getFieldOptions(typeOf, field).TSDoc; doc != "" {
+			schema["description"] = doc
+		}
+		properties[name] = schema
+		if !strings.HasSuffix(jsonFieldName, "?") {
+			required = append(required, name)
+		}
+	}
+
+	result := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		result["required"] = required
+	}
+	if field, found := inlineMapField(typeOf); found {
+		mapType := field.Type
+		if mapType.Kind() == reflect.Ptr {
+			mapType = mapType.Elem()
+		}
+		values, err := t.jsonSchema(mapType.Elem(), defs)
+		if err != nil {
+			return nil, err
+		}
+		result["additionalProperties"] = values
+	}
+	return result, nil
+}
@@ -0,0 +1,269 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+	type Event struct {
+		Name     string            `json:"name" ts_doc:"Name of the event"`
+		Weekday  Weekday           `json:"weekday"`
+		Time     time.Time         `json:"time"`
+		Place    *Address          `json:"place"`
+		Days     [2]int            `json:"days,omitempty"`
+		Labels   map[string]string `json:"labels"`
+		Shape    Shape             `json:"shape"`
+		Page     Page[HasName]     `json:"page"`
+		Children []Event           `json:"children"`
+	}
+
+	converter := New().
+		AddEnum(allGenders).
+		AddEnum(allWeekdaysV1).
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}}).
+		Add(Event{}).
+		Add(Page[Dummy]{})
+
+	schema, err := converter.ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs": {
+			"Gender": {"enum": ["m", "f"]},
+			"Weekday": {"enum": [0, 1, 2, 3, 4, 5, 6]},
+			"Circle": {
+				"type": "object",
+				"properties": {"kind": {"const": "circle"}, "radius": {"type": "number"}},
+				"required": ["kind", "radius"]
+			},
+			"Square": {
+				"type": "object",
+				"properties": {"kind": {"const": "square"}, "side": {"type": "number"}},
+				"required": ["kind", "side"]
+			},
+			"Shape": {"oneOf": [{"$ref": "#/$defs/Circle"}, {"$ref": "#/$defs/Square"}]},
+			"Address": {
+				"type": "object",
+				"properties": {"duration": {"type": "number"}, "text": {"type": "string"}, "Text2": {"type": "string"}},
+				"required": ["duration"]
+			},
+			"HasName": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}},
+				"required": ["name"]
+			},
+			"Dummy": {
+				"type": "object",
+				"properties": {"something": {"type": "string"}},
+				"required": ["something"]
+			},
+			"Page_HasName": {
+				"type": "object",
+				"properties": {"items": {"anyOf": [{"type": "array", "items": {"$ref": "#/$defs/HasName"}}, {"type": "null"}]}, "total": {"type": "integer"}},
+				"required": ["items", "total"]
+			},
+			"Page_Dummy": {
+				"type": "object",
+				"properties": {"items": {"anyOf": [{"type": "array", "items": {"$ref": "#/$defs/Dummy"}}, {"type": "null"}]}, "total": {"type": "integer"}},
+				"required": ["items", "total"]
+			},
+			"Event": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "description": "Name of the event"},
+					"weekday": {"$ref": "#/$defs/Weekday"},
+					"time": {"type": "string", "format": "date-time"},
+					"place": {"anyOf": [{"$ref": "#/$defs/Address"}, {"type": "null"}]},
+					"days": {"type": "array", "items": {"type": "integer"}, "minItems": 2, "maxItems": 2},
+					"labels": {"anyOf": [{"type": "object", "additionalProperties": {"type": "string"}}, {"type": "null"}]},
+					"shape": {"$ref": "#/$defs/Shape"},
+					"page": {"$ref": "#/$defs/Page_HasName"},
+					"children": {"anyOf": [{"type": "array", "items": {"$ref": "#/$defs/Event"}}, {"type": "null"}]}
+				},
+				"required": ["name", "weekday", "time", "labels", "shape", "page", "children"]
+			}
+		}
+	}`, schema)
+
+	// The required fields must be in the JSON:
+	var decoded map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(jsonizeOrPanic(Event{})), &decoded))
+	for _, fld := range []string{"name", "weekday", "time", "labels", "shape", "page", "children"} {
+		assert.Contains(t, decoded, fld)
+	}
+}
+
+func TestJSONSchemaAcceptsZeroValues(t *testing.T) {
+	t.Parallel()
+	type Event struct {
+		Name     string            `json:"name"`
+		Weekday  Weekday           `json:"weekday"`
+		Time     time.Time         `json:"time"`
+		Place    *Address          `json:"place"`
+		Backup   *Address          `json:"backup,omitempty"`
+		Labels   map[string]string `json:"labels"`
+		Page     Page[HasName]     `json:"page"`
+		Children []Event           `json:"children"`
+		Data     []byte            `json:"data"`
+	}
+
+	// Nil pointers, slices and maps are `null` in the JSON, whatever the nullability mode:
+	for _, mode := range []NullabilityMode{NullAsOptional, NullAsNullable, NullAsOptionalNullable} {
+		schema, err := New().AddEnum(allWeekdaysV1).Add(Event{}).WithNullability(mode).ConvertJSONSchema()
+		assert.Nil(t, err)
+		var decodedSchema, decoded map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(schema), &decodedSchema))
+		assert.Nil(t, json.Unmarshal([]byte(jsonizeOrPanic(Event{})), &decoded))
+		defs := decodedSchema["$defs"].(map[string]interface{})
+		assert.True(t, matchesJSONSchema(defs, defs["Event"], decoded), "mode %d", mode)
+		assert.False(t, matchesJSONSchema(defs, defs["Event"], map[string]interface{}{}), "mode %d", mode)
+	}
+}
+
+// matchesJSONSchema validates a decoded JSON value, only the keywords used by ConvertJSONSchema() are supported.
+func matchesJSONSchema(defs map[string]interface{}, schemaValue, value interface{}) bool {
+	schema := schemaValue.(map[string]interface{})
+	if ref, is := schema["$ref"].(string); is {
+		return matchesJSONSchema(defs, defs[strings.TrimPrefix(ref, "#/$defs/")], value)
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		if alternatives, is := schema[keyword].([]interface{}); is {
+			matches := 0
+			for _, alternative := range alternatives {
+				if matchesJSONSchema(defs, alternative, value) {
+					matches++
+				}
+			}
+			if matches == 0 || (keyword == "oneOf" && matches > 1) {
+				return false
+			}
+		}
+	}
+	if constant, is := schema["const"]; is && constant != value {
+		return false
+	}
+	if enum, is := schema["enum"].([]interface{}); is {
+		found := false
+		for _, v := range enum {
+			found = found || v == value
+		}
+		if !found {
+			return false
+		}
+	}
+	switch schema["type"] {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, is := value.(bool)
+		return is
+	case "string":
+		_, is := value.(string)
+		return is
+	case "number":
+		_, is := value.(float64)
+		return is
+	case "integer":
+		f, is := value.(float64)
+		return is && f == float64(int64(f))
+	case "array":
+		arr, is := value.([]interface{})
+		if !is {
+			return false
+		}
+		if min, is := schema["minItems"].(float64); is && float64(len(arr)) < min {
+			return false
+		}
+		if max, is := schema["maxItems"].(float64); is && float64(len(arr)) > max {
+			return false
+		}
+		for _, item := range arr {
+			if !matchesJSONSchema(defs, schema["items"], item) {
+				return false
+			}
+		}
+	case "object":
+		obj, is := value.(map[string]interface{})
+		if !is {
+			return false
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, found := obj[name.(string)]; !found {
+				return false
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, v := range obj {
+			if propertySchema, found := properties[name]; found {
+				if !matchesJSONSchema(defs, propertySchema, v) {
+					return false
+				}
+			} else if additional, found := schema["additionalProperties"]; found && !matchesJSONSchema(defs, additional, v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type Decimal struct {
+	Scale int
+}
+
+func (Decimal) MarshalJSON() ([]byte, error) { return []byte(`"1.00"`), nil }
+
+func TestJSONSchemaWithManagedTypes(t *testing.T) {
+	t.Parallel()
+	type Invoice struct {
+		Total   Decimal   `json:"total"`
+		Tax     *Decimal  `json:"tax"`
+		Paid    time.Time `json:"paid"`
+		Address Address   `json:"address"`
+	}
+
+	// The TypeScript types don't change the JSON of custom marshalers (or of structs without them):
+	schema, err := New().
+		Add(Invoice{}).
+		ManageType(Decimal{}, TypeOptions{TSType: "Decimal"}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)"}).
+		ManageType(Address{}, TypeOptions{TSType: "Location"}).
+		ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs": {
+			"Address": {
+				"type": "object",
+				"properties": {"duration": {"type": "number"}, "text": {"type": "string"}, "Text2": {"type": "string"}},
+				"required": ["duration"]
+			},
+			"Invoice": {
+				"type": "object",
+				"properties": {
+					"total": {"type": "string"},
+					"tax": {"anyOf": [{"type": "string"}, {"type": "null"}]},
+					"paid": {"type": "string", "format": "date-time"},
+					"address": {"$ref": "#/$defs/Address"}
+				},
+				"required": ["total", "paid", "address"]
+			}
+		}
+	}`, schema)
+}
+
+func TestJSONSchemaUnsupportedType(t *testing.T) {
+	t.Parallel()
+	type WithChan struct {
+		Ch chan int `json:"ch"`
+	}
+
+	_, err := New().Add(WithChan{}).ConvertJSONSchema()
+	assert.EqualError(t, err, "cannot find JSON schema type for chan (chan int)")
+}
@@ -0,0 +1,63 @@
+package typescriptify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tsLiteral returns the TypeScript literal of a string, number or bool value (used for enum members and constants).
+// Integers are exact decimal numbers, even if JavaScript numbers can't represent them (see `isSafeInteger()`).
+func tsLiteral(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return jsonString(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		switch {
+		case math.IsNaN(f):
+			return "NaN", nil
+		case math.IsInf(f, 1):
+			return "Infinity", nil
+		case math.IsInf(f, -1):
+			return "-Infinity", nil
+		}
+		bitSize := 64
+		if v.Kind() == reflect.Float32 {
+			bitSize = 32
+		}
+		if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+			// Like JavaScript's Number.prototype.toString(), without the leading zeros of Go's exponent (1e-07 => 1e-7):
+			literal := strconv.FormatFloat(f, 'e', -1, bitSize)
+			i := strings.IndexByte(literal, 'e') + 2 // After the sign of the exponent
+			return literal[:i] + strings.TrimLeft(literal[i:], "0"), nil
+		}
+		// -0 is kept, it's a different value in JavaScript (Object.is(-0, 0) is false):
+		return strconv.FormatFloat(f, 'f', -1, bitSize), nil
+	}
+	if !v.IsValid() {
+		return "", fmt.Errorf("nil can't be converted to a TypeScript literal")
+	}
+	return "", fmt.Errorf("%s values can't be converted to TypeScript literals", v.Type().String())
+}
+
+// jsonString returns a string literal, JSON strings are valid TypeScript strings.
+func jsonString(s string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
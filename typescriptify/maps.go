@@ -0,0 +1,90 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithStringMapKeys sets if integer map keys are converted to `string` (like they are encoded in JSON) instead of
+// `number`.
+func (t *TypeScriptify) WithStringMapKeys(b bool) *TypeScriptify {
+	t.StringMapKeys = b
+	return t
+}
+
+// WithRecordMaps sets if maps are converted to `Record<K, V>` instead of index signatures (`{[key: K]: V}`).
+func (t *TypeScriptify) WithRecordMaps(b bool) *TypeScriptify {
+	t.RecordMaps = b
+	return t
+}
+
+// mapKeyType returns the TypeScript type of a map key, resolved like in `encoding/json`: string keys are used as they
+// are, `encoding.TextMarshaler` keys are encoded as their text and integer keys as decimal numbers. Keys of registered
+// enums are the enum type (isEnum=true). Other key types can't be encoded.
+func (t *TypeScriptify) mapKeyType(key reflect.Type) (tsType string, isEnum bool, err error) {
+	_, isEnum = t.enums[key]
+	switch key.Kind() {
+	case reflect.String:
+		if isEnum {
+			return t.typeName(key), true, nil
+		}
+		return "string", false, nil
+	}
+	if key.Implements(textMarshalerType) {
+		return "string", false, nil
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if t.StringMapKeys {
+			return "string", false, nil
+		}
+		if isEnum {
+			return t.typeName(key), true, nil
+		}
+		return "number", false, nil
+	}
+	return "", false, fmt.Errorf("map key type %s can't be encoded to JSON", key.String())
+}
+
+// mapType returns the TypeScript type of a map with the already converted value type. Maps with enum keys are
+// `Partial<Record<Enum, V>>` because not all enum values must be present.
+func (t *TypeScriptify) mapType(key reflect.Type, valueType string) string {
+	keyType, isEnum, err := t.mapKeyType(key)
+	if err != nil { // Already reported by collectMarshalers()
+		keyType = "string"
+	}
+	switch {
+	case isEnum:
+		return fmt.Sprintf("Partial<Record<%s, %s>>", t.reference(keyType), valueType)
+	case t.RecordMaps:
+		return fmt.Sprintf("Record<%s, %s>", keyType, valueType)
+	}
+	return fmt.Sprintf("{[key: %s]: %s}", keyType, valueType)
+}
+
+// mapKeyZodSchema returns the zod schema for the keys of a map. Keys in JSON are always strings, only enum keys are
+// validated.
+func (t *TypeScriptify) mapKeyZodSchema(key reflect.Type) string {
+	if _, isEnum, err := t.mapKeyType(key); err == nil && isEnum && key.Kind() == reflect.String {
+		return t.reference(zodSchemaName(t.typeName(key)))
+	}
+	return "z.string()"
+}
+
+// mapKeyJSONSchema returns the JSON Schema for the property names of a map, or nil if any string is valid.
+func (t *TypeScriptify) mapKeyJSONSchema(key reflect.Type, defs map[string]interface{}) (map[string]interface{}, error) {
+	_, isEnum, err := t.mapKeyType(key)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case key.Kind() == reflect.String:
+		if isEnum {
+			return t.jsonSchema(key, defs)
+		}
+	case !key.Implements(textMarshalerType):
+		return map[string]interface{}{"pattern": "^-?[0-9]+$"}, nil
+	}
+	return nil, nil
+}
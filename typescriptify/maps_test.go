@@ -0,0 +1,309 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Inventory struct {
+	ByID     map[int]Address    `json:"by_id"`
+	ByGender map[Gender]int     `json:"by_gender"`
+	ByDay    map[Weekday]string `json:"by_day"`
+	ByLevel  map[Level]bool     `json:"by_level"`
+	ByName   map[string]float64 `json:"by_name"`
+}
+
+func TestMapKeys(t *testing.T) {
+	t.Parallel()
+
+	// Integer keys are decimal strings, TextMarshaler keys their text:
+	byts, err := json.Marshal(Inventory{ByID: map[int]Address{7: {}}, ByLevel: map[Level]bool{1: true}})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"by_id":{"7":{"duration":0}},"by_gender":null,"by_day":null,"by_level":{"high":true},"by_name":null}`, string(byts))
+
+	converter := New().
+		AddEnum(allGenders).
+		AddEnum(allWeekdaysV1).
+		Add(Inventory{}).
+		WithBackupDir("")
+
+	desiredResult := `export enum Gender {
+    MALE = "m",
+    FEMALE = "f",
+}
+export enum Weekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export class Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.duration = source["duration"];
+        this.text = source["text"];
+        this.Text2 = source["Text2"];
+    }
+}
+export class Inventory {
+    by_id: {[key: number]: Address};
+    by_gender: Partial<Record<Gender, number>>;
+    by_day: Partial<Record<Weekday, string>>;
+    by_level: {[key: string]: boolean};
+    by_name: {[key: string]: number};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.by_id = this.convertValues(source["by_id"], Address, true);
+        this.by_gender = source["by_gender"];
+        this.by_day = source["by_day"];
+        this.by_level = source["by_level"];
+        this.by_name = source["by_name"];
+    }
+
+	` + tsConvertValuesFunc + `
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Inventory({"by_id": {"7": {"duration": 1}}}).by_id[7] instanceof Address`,
+		`new Inventory({"by_gender": {"m": 2}}).by_gender[Gender.MALE] === 2`,
+	})
+}
+
+// inventoryInterfaces are the enums and interfaces used by Inventory (without it).
+const inventoryInterfaces = `export enum Gender {
+    MALE = "m",
+    FEMALE = "f",
+}
+export enum Weekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export interface Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+}`
+
+func TestMapKeysAsStrings(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddEnum(allGenders).
+		AddEnum(allWeekdaysV1).
+		Add(Inventory{}).
+		WithStringMapKeys(true).
+		WithInterface(true).
+		WithBackupDir("")
+	desiredResult := inventoryInterfaces + `
+export interface Inventory {
+    by_id: {[key: string]: Address};
+    by_gender: Partial<Record<Gender, number>>;
+    by_day: {[key: string]: string};
+    by_level: {[key: string]: boolean};
+    by_name: {[key: string]: number};
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+type PagedCounts struct {
+	Counts Page[map[int]string] `json:"counts"`
+	Names  Page[string]         `json:"names"`
+}
+
+func TestRecordMaps(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddEnum(allGenders).
+		AddEnum(allWeekdaysV1).
+		Add(Inventory{}).
+		Add(PagedCounts{}).
+		WithRecordMaps(true).
+		WithInterface(true).
+		WithBackupDir("")
+	desiredResult := inventoryInterfaces + `
+export interface Inventory {
+    by_id: Record<number, Address>;
+    by_gender: Partial<Record<Gender, number>>;
+    by_day: Partial<Record<Weekday, string>>;
+    by_level: Record<string, boolean>;
+    by_name: Record<string, number>;
+}
+export interface Page<T> {
+    items: T[];
+    total: number;
+}
+export interface PagedCounts {
+    counts: Page<Record<number, string>>;
+    names: Page<string>;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestMapKeysZodGuardsAndJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		AddEnum(allGenders).
+		AddEnum(allWeekdaysV1).
+		Add(Inventory{}).
+		WithZodSchema(true).
+		WithBackupDir("")
+	desiredResult := `import { z } from "zod";
+
+export enum Gender {
+    MALE = "m",
+    FEMALE = "f",
+}
+export const GenderSchema = z.nativeEnum(Gender);
+export enum Weekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export const WeekdaySchema = z.nativeEnum(Weekday);
+export const AddressSchema = z.object({
+    duration: z.number(),
+    text: z.string().optional(),
+    Text2: z.string().optional(),
+});
+export type Address = z.infer<typeof AddressSchema>;
+export const InventorySchema = z.object({
+    by_id: z.record(z.string(), AddressSchema),
+    by_gender: z.record(GenderSchema, z.number()),
+    by_day: z.record(z.string(), z.string()),
+    by_level: z.record(z.string(), z.boolean()),
+    by_name: z.record(z.string(), z.number()),
+});
+export type Inventory = z.infer<typeof InventorySchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	converter = New().
+		AddEnum(allGenders).
+		AddEnum(allWeekdaysV1).
+		Add(Inventory{}).
+		WithTypeGuards(true).
+		WithInterface(true).
+		WithBackupDir("")
+	desiredResult = `export enum Gender {
+    MALE = "m",
+    FEMALE = "f",
+}
+export function isGender(x: unknown): x is Gender {
+    return ([Gender.MALE, Gender.FEMALE] as unknown[]).includes(x);
+}
+export enum Weekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export function isWeekday(x: unknown): x is Weekday {
+    return ([Weekday.SUNDAY, Weekday.MONDAY, Weekday.TUESDAY, Weekday.WEDNESDAY, Weekday.THURSDAY, Weekday.FRIDAY, Weekday.SATURDAY] as unknown[]).includes(x);
+}
+export interface Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+}
+export function isAddress(x: unknown): x is Address {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "number" === typeof o["duration"]
+        && (o["text"] === undefined || "string" === typeof o["text"])
+        && (o["Text2"] === undefined || "string" === typeof o["Text2"]);
+}
+export interface Inventory {
+    by_id: {[key: number]: Address};
+    by_gender: Partial<Record<Gender, number>>;
+    by_day: Partial<Record<Weekday, string>>;
+    by_level: {[key: string]: boolean};
+    by_name: {[key: string]: number};
+}
+export function isInventory(x: unknown): x is Inventory {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return (o["by_id"] === null || "object" === typeof o["by_id"] && o["by_id"] !== null && !Array.isArray(o["by_id"]) && Object.values(o["by_id"]).every((v1: unknown) => isAddress(v1)))
+        && (o["by_gender"] === null || "object" === typeof o["by_gender"] && o["by_gender"] !== null && !Array.isArray(o["by_gender"]) && Object.values(o["by_gender"]).every((v1: unknown) => "number" === typeof v1) && Object.keys(o["by_gender"]).every((v1: string) => isGender(v1)))
+        && (o["by_day"] === null || "object" === typeof o["by_day"] && o["by_day"] !== null && !Array.isArray(o["by_day"]) && Object.values(o["by_day"]).every((v1: unknown) => "string" === typeof v1))
+        && (o["by_level"] === null || "object" === typeof o["by_level"] && o["by_level"] !== null && !Array.isArray(o["by_level"]) && Object.values(o["by_level"]).every((v1: unknown) => "boolean" === typeof v1))
+        && (o["by_name"] === null || "object" === typeof o["by_name"] && o["by_name"] !== null && !Array.isArray(o["by_name"]) && Object.values(o["by_name"]).every((v1: unknown) => "number" === typeof v1));
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isInventory({"by_id": {}, "by_gender": {"m": 1}, "by_day": {}, "by_level": {}, "by_name": {}})`,
+		`!isInventory({"by_id": {}, "by_gender": {"x": 1}, "by_day": {}, "by_level": {}, "by_name": {}})`,
+	})
+
+	schema, err := New().
+		AddEnum(allGenders).
+		AddEnum(allWeekdaysV1).
+		Add(Inventory{}).
+		WithBackupDir("").
+		ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "Gender": {"enum": ["m", "f"]},
+        "Weekday": {"enum": [0, 1, 2, 3, 4, 5, 6]},
+        "Address": {
+            "type": "object",
+            "properties": {
+                "duration": {"type": "number"},
+                "text": {"type": "string"},
+                "Text2": {"type": "string"}
+            },
+            "required": ["duration"]
+        },
+        "Inventory": {
+            "type": "object",
+            "properties": {
+                "by_id": {"anyOf": [{"type": "object", "propertyNames": {"pattern": "^-?[0-9]+$"}, "additionalProperties": {"$ref": "#/$defs/Address"}}, {"type": "null"}]},
+                "by_gender": {"anyOf": [{"type": "object", "propertyNames": {"$ref": "#/$defs/Gender"}, "additionalProperties": {"type": "integer"}}, {"type": "null"}]},
+                "by_day": {"anyOf": [{"type": "object", "propertyNames": {"pattern": "^-?[0-9]+$"}, "additionalProperties": {"type": "string"}}, {"type": "null"}]},
+                "by_level": {"anyOf": [{"type": "object", "additionalProperties": {"type": "boolean"}}, {"type": "null"}]},
+                "by_name": {"anyOf": [{"type": "object", "additionalProperties": {"type": "number"}}, {"type": "null"}]}
+            },
+            "required": ["by_id", "by_gender", "by_day", "by_level", "by_name"]
+        }
+    }
+}`, schema)
+}
+
+func TestUnsupportedMapKeys(t *testing.T) {
+	t.Parallel()
+
+	type WithBoolKeys struct {
+		Values map[bool]string `json:"values"`
+	}
+	_, err := New().Add(WithBoolKeys{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "map key type bool can't be encoded to JSON")
+
+	_, err = json.Marshal(WithBoolKeys{Values: map[bool]string{true: ""}})
+	assert.NotNil(t, err)
+}
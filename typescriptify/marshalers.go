@@ -0,0 +1,199 @@
+package typescriptify
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// WithInferMarshalJSON sets if the JSON type of types with a custom `MarshalJSON()` is inferred by marshalling their
+// zero value (default). If false, such types need a `ts_type` tag or `ManageType()`, or the conversion fails.
+func (t *TypeScriptify) WithInferMarshalJSON(b bool) *TypeScriptify {
+	t.InferMarshalJSON = b
+	return t
+}
+
+// collectMarshalers finds all types with a custom JSON encoding (`json.Marshaler` or `encoding.TextMarshaler`) used
+// in the converted types, and saves their JSON kind (`string`, `number`, `boolean`, `array` or `object`) in
+// `t.marshalers`. Enums, unions and managed types are converted as usual. It fails on registered structs (and union
+// variants) with a custom encoding, since their declaration wouldn't match the JSON, and on map keys which can't be
+// encoded (see `mapKeyType()`).
+func (t *TypeScriptify) collectMarshalers() error {
+	t.marshalers = map[reflect.Type]string{}
+
+	registered := map[reflect.Type]bool{}
+	for _, strctTyp := range t.structTypes {
+		registered[strctTyp.Type] = true
+	}
+	for _, union := range t.unions {
+		for _, variant := range union.Variants {
+			registered[variant.Type] = true
+		}
+	}
+
+	visited := map[reflect.Type]bool{}
+	var walk func(typ reflect.Type) error
+	walk = func(typ reflect.Type) error {
+		if visited[typ] {
+			return nil
+		}
+		visited[typ] = true
+		if union, isUnion := t.unions[typ]; isUnion {
+			for _, variant := range union.Variants {
+				if err := walk(variant.Type); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if _, isEnum := t.enums[typ]; isEnum {
+			return nil
+		}
+		if opts, found := t.typeOptions(typ); found && opts.TSType != "" {
+			return nil
+		}
+		if typ.Kind() != reflect.Ptr {
+			kind, is, err := t.marshalerKind(typ)
+			if err != nil {
+				return err
+			}
+			if is && registered[typ] {
+				return fmt.Errorf("%s is encoded as a JSON %s by its custom marshaler, it can't be converted as a struct (set its TypeScript type with ManageType())", typ.String(), kind)
+			}
+			if is {
+				t.marshalers[typ] = kind
+				return nil
+			}
+		}
+		switch typ.Kind() {
+		case reflect.Map:
+			if _, _, err := t.mapKeyType(typ.Key()); err != nil {
+				return err
+			}
+			return walk(typ.Elem())
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			return walk(typ.Elem())
+		case reflect.Struct:
+			for _, field := range deepFields(typ) {
+				jsonFieldName := t.getJSONFieldName(field, false)
+				if isInlineMap(field) {
+					jsonFieldName = field.Name
+				}
+				if len(jsonFieldName) == 0 {
+					continue
+				}
+				fieldType := field.Type
+				if field.Type.Kind() == reflect.Ptr {
+					field.Type = field.Type.Elem()
+				}
+				if t.getFieldOptions(typ, field).TSType != "" {
+					continue
+				}
+				if err := walk(fieldType); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, unionTyp := range t.unionTypes {
+		if err := walk(unionTyp); err != nil {
+			return err
+		}
+	}
+	for _, strctTyp := range t.structTypes {
+		if err := walk(strctTyp.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalerKind returns the JSON kind of a type implementing `json.Marshaler` or `encoding.TextMarshaler` (with a value
+// or pointer receiver). Like in `encoding/json`, `MarshalJSON()` takes precedence over `MarshalText()`.
+func (t *TypeScriptify) marshalerKind(typ reflect.Type) (kind string, is bool, err error) {
+	ptr := reflect.PtrTo(typ)
+	if ptr.Implements(jsonMarshalerType) {
+		if !t.InferMarshalJSON {
+			return "", true, fmt.Errorf("%s implements json.Marshaler, set its TypeScript type with a ts_type tag or ManageType()", typ.String())
+		}
+		kind, err := inferJSONKind(typ)
+		if err != nil {
+			return "", true, fmt.Errorf("cannot infer the JSON type of %s (%s), set its TypeScript type with a ts_type tag or ManageType()", typ.String(), err.Error())
+		}
+		return kind, true, nil
+	}
+	if ptr.Implements(textMarshalerType) {
+		return "string", true, nil
+	}
+	return "", false, nil
+}
+
+// inferJSONKind marshals the zero value of a type and returns the kind of the resulting JSON value.
+func inferJSONKind(typ reflect.Type) (kind string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("MarshalJSON() panics on the zero value: %v", r)
+		}
+	}()
+
+	byts, err := json.Marshal(reflect.New(typ).Interface())
+	if err != nil {
+		return "", err
+	}
+	byts = bytes.TrimSpace(byts)
+	switch {
+	case len(byts) == 0:
+		return "", fmt.Errorf("MarshalJSON() returns nothing for the zero value")
+	case byts[0] == '"':
+		return "string", nil
+	case byts[0] == '-' || (byts[0] >= '0' && byts[0] <= '9'):
+		return "number", nil
+	case byts[0] == 't' || byts[0] == 'f':
+		return "boolean", nil
+	case byts[0] == '[':
+		return "array", nil
+	case byts[0] == '{':
+		return "object", nil
+	}
+	return "", fmt.Errorf("MarshalJSON() returns %s for the zero value", string(byts))
+}
+
+// marshalerType returns the TypeScript type and zod schema of a type with a custom JSON encoding (or a pointer, slice,
+// array or map of it).
+func (t *TypeScriptify) marshalerType(typ reflect.Type) (tsType string, zodSchema string, is bool) {
+	switch t.marshalers[typ] {
+	case "string":
+		return "string", "z.string()", true
+	case "number":
+		return "number", "z.number()", true
+	case "boolean":
+		return "boolean", "z.boolean()", true
+	case "array":
+		return "any[]", "z.array(z.any())", true
+	case "object":
+		return "{[key: string]: any}", "z.record(z.string(), z.any())", true
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.marshalerType(typ.Elem())
+	case reflect.Slice, reflect.Array:
+		if tsType, zodSchema, is := t.marshalerType(typ.Elem()); is {
+			return tsType + "[]", "z.array(" + zodSchema + ")", true
+		}
+	case reflect.Map:
+		if tsType, zodSchema, is := t.marshalerType(typ.Elem()); is {
+			return t.mapType(typ.Key(), tsType), "z.record(" + t.mapKeyZodSchema(typ.Key()) + ", " + zodSchema + ")", true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,392 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Celsius struct {
+	degrees float64
+}
+
+func (c Celsius) MarshalJSON() ([]byte, error) { return json.Marshal(c.degrees) }
+
+type Flag struct {
+	set bool
+}
+
+func (f *Flag) MarshalJSON() ([]byte, error) { return json.Marshal(f.set) }
+
+type Level int
+
+func (l Level) MarshalText() ([]byte, error) { return []byte([]string{"low", "high"}[l%2]), nil }
+
+type Reading struct {
+	Time        time.Time             `json:"time"`
+	Temperature Celsius               `json:"temperature"`
+	Enabled     *Flag                 `json:"enabled"`
+	Level       Level                 `json:"level"`
+	IP          net.IP                `json:"ip"`
+	History     []Celsius             `json:"history"`
+	Levels      map[string]Level      `json:"levels"`
+	Overridden  Celsius               `json:"overridden" ts_type:"string"`
+	Nested      map[string][]*Celsius `json:"nested"`
+}
+
+func TestMarshalers(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Reading{}).
+		WithBackupDir("")
+
+	desiredResult := `export class Reading {
+    time: string;
+    temperature: number;
+    enabled?: boolean;
+    level: string;
+    ip: string;
+    history: number[];
+    levels: {[key: string]: string};
+    overridden: string;
+    nested: {[key: string]: number[]};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.time = source["time"];
+        this.temperature = source["temperature"];
+        this.enabled = source["enabled"];
+        this.level = source["level"];
+        this.ip = source["ip"];
+        this.history = source["history"];
+        this.levels = source["levels"];
+        this.overridden = source["overridden"];
+        this.nested = source["nested"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+
+	byts, err := json.Marshal(Reading{Level: 1, History: []Celsius{{degrees: 21.5}}})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"time":"0001-01-01T00:00:00Z","temperature":0,"enabled":null,"level":"high","ip":"","history":[21.5],"levels":null,"overridden":0,"nested":null}`, string(byts))
+}
+
+func TestMarshalersInGenericsZodAndGuards(t *testing.T) {
+	t.Parallel()
+
+	// The type parameter is a custom marshaler in one instantiation, and a struct in the other:
+	type Measurements struct {
+		Temperatures Page[Celsius] `json:"temperatures"`
+		Names        Page[HasName] `json:"names"`
+	}
+	converter := New().Add(Measurements{}).WithBackupDir("")
+	desiredResult := `export class HasName {
+    name: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+    }
+}
+export class Page<T> {
+    items: T[];
+    total: number;
+
+    constructor(source: any = {}, convertT: (v: any) => any = (v) => v) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.items = ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => convertT(e1)))(source["items"]);
+        this.total = source["total"];
+    }
+}
+export class Measurements {
+    temperatures: Page<number>;
+    names: Page<HasName>;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.temperatures = this.convertValues(source["temperatures"], Page, false, undefined);
+        this.names = this.convertValues(source["names"], Page, false, (v1: any) => this.convertValues(v1, HasName));
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false, ...typeArgs: any[]): any {
+	    if (!a || !classs) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs, false, ...typeArgs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = this.convertValues(a[key], classs, false, ...typeArgs);
+	            }
+	            return a;
+	        }
+	        if (classs.variants) {
+	            return this.convertValues(a, classs.variants[a[classs.discriminator]], false, ...typeArgs);
+	        }
+	        return new classs(a, ...typeArgs);
+	    }
+	    return a;
+	}
+}`
+	source := `{"temperatures": {"items": [21.5], "total": 1}, "names": {"items": [{"name": "a"}], "total": 1}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Measurements(` + source + `).temperatures.items[0] === 21.5`,
+		`new Measurements(` + source + `).names.items[0] instanceof HasName`,
+	})
+
+	converter = New().Add(Measurements{}).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export const HasNameSchema = z.object({
+    name: z.string(),
+});
+export type HasName = z.infer<typeof HasNameSchema>;
+export interface Page<T> {
+    items: T[];
+    total: number;
+}
+export const PageSchema = <T extends z.ZodTypeAny>(T: T) => z.object({
+    items: z.array(T),
+    total: z.number(),
+});
+export const MeasurementsSchema = z.object({
+    temperatures: PageSchema(z.number()),
+    names: PageSchema(HasNameSchema),
+});
+export type Measurements = z.infer<typeof MeasurementsSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	converter = New().Add(Measurements{}).WithInterface(true).WithTypeGuards(true).WithBackupDir("")
+	desiredResult = `export interface HasName {
+    name: string;
+}
+export function isHasName(x: unknown): x is HasName {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"];
+}
+export interface Page<T> {
+    items: T[];
+    total: number;
+}
+export function isPage<T>(x: unknown, isT: (x: unknown) => x is T): x is Page<T> {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return (o["items"] === null || Array.isArray(o["items"]) && o["items"].every((v1: unknown) => isT(v1)))
+        && "number" === typeof o["total"];
+}
+export interface Measurements {
+    temperatures: Page<number>;
+    names: Page<HasName>;
+}
+export function isMeasurements(x: unknown): x is Measurements {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return isPage(o["temperatures"], (v1: unknown): v1 is number => "number" === typeof v1)
+        && isPage(o["names"], (v1: unknown): v1 is HasName => isHasName(v1));
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isMeasurements(` + source + `)`,
+		`!isMeasurements({"temperatures": {"items": [{"name": "a"}], "total": 1}, "names": {"items": [], "total": 0}})`,
+	})
+
+	converter = New().Add(Reading{}).WithZodSchema(true).WithBackupDir("")
+	desiredResult = `import { z } from "zod";
+
+export const ReadingSchema = z.object({
+    time: z.string(),
+    temperature: z.number(),
+    enabled: z.boolean().optional(),
+    level: z.string(),
+    ip: z.string(),
+    history: z.array(z.number()),
+    levels: z.record(z.string(), z.string()),
+    overridden: z.custom<string>(),
+    nested: z.record(z.string(), z.array(z.number())),
+});
+export type Reading = z.infer<typeof ReadingSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	converter = New().Add(Reading{}).WithInterface(true).WithTypeGuards(true).WithBackupDir("")
+	desiredResult = `export interface Reading {
+    time: string;
+    temperature: number;
+    enabled?: boolean;
+    level: string;
+    ip: string;
+    history: number[];
+    levels: {[key: string]: string};
+    overridden: string;
+    nested: {[key: string]: number[]};
+}
+export function isReading(x: unknown): x is Reading {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["time"]
+        && "number" === typeof o["temperature"]
+        && (o["enabled"] === undefined || o["enabled"] === null || "boolean" === typeof o["enabled"])
+        && "string" === typeof o["level"]
+        && "string" === typeof o["ip"]
+        && (o["history"] === null || Array.isArray(o["history"]) && o["history"].every((v1: unknown) => "number" === typeof v1))
+        && (o["levels"] === null || "object" === typeof o["levels"] && o["levels"] !== null && !Array.isArray(o["levels"]) && Object.values(o["levels"]).every((v1: unknown) => "string" === typeof v1))
+        && o["overridden"] !== undefined
+        && (o["nested"] === null || "object" === typeof o["nested"] && o["nested"] !== null && !Array.isArray(o["nested"]) && Object.values(o["nested"]).every((v1: unknown) => Array.isArray(v1) && v1.every((v2: unknown) => "number" === typeof v2)));
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+type NullTime struct {
+	t *time.Time
+}
+
+func (n NullTime) MarshalJSON() ([]byte, error) {
+	if n.t == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.t)
+}
+
+type FailingMarshaler struct{}
+
+func (FailingMarshaler) MarshalJSON() ([]byte, error) { return nil, errors.New("always fails") }
+
+type PanickingMarshaler struct {
+	value *float64
+}
+
+func (p PanickingMarshaler) MarshalJSON() ([]byte, error) { return json.Marshal(*p.value) }
+
+func TestMarshalersWhichCannotBeInferred(t *testing.T) {
+	t.Parallel()
+
+	type WithNullTime struct {
+		Time NullTime `json:"time"`
+	}
+	_, err := New().Add(WithNullTime{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "cannot infer the JSON type of typescriptify.NullTime (MarshalJSON() returns null for the zero value), set its TypeScript type with a ts_type tag or ManageType()")
+
+	type WithFailingMarshaler struct {
+		Value FailingMarshaler `json:"value"`
+	}
+	_, err = New().Add(WithFailingMarshaler{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "cannot infer the JSON type of typescriptify.FailingMarshaler (json: error calling MarshalJSON for type *typescriptify.FailingMarshaler: always fails), set its TypeScript type with a ts_type tag or ManageType()")
+
+	type WithPanickingMarshaler struct {
+		Value PanickingMarshaler `json:"value"`
+	}
+	_, err = New().Add(WithPanickingMarshaler{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "cannot infer the JSON type of typescriptify.PanickingMarshaler (MarshalJSON() panics on the zero value: runtime error: invalid memory address or nil pointer dereference), set its TypeScript type with a ts_type tag or ManageType()")
+
+	// Overrides are used instead:
+	converter := New().
+		Add(WithNullTime{}).
+		ManageType(NullTime{}, TypeOptions{TSType: "string | null"}).
+		WithBackupDir("")
+	desiredResult := `export class WithNullTime {
+    time: string | null;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.time = source["time"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestMarshalersWithoutInference(t *testing.T) {
+	t.Parallel()
+
+	_, err := New().Add(Reading{}).WithInferMarshalJSON(false).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "time.Time implements json.Marshaler, set its TypeScript type with a ts_type tag or ManageType()")
+
+	// TextMarshalers are still strings:
+	type WithLevel struct {
+		Level Level `json:"level"`
+	}
+	converter := New().Add(WithLevel{}).WithInferMarshalJSON(false).WithBackupDir("")
+	desiredResult := `export class WithLevel {
+    level: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.level = source["level"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestMarshalersJSONSchema(t *testing.T) {
+	t.Parallel()
+	schema, err := New().Add(Reading{}).WithBackupDir("").ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "Reading": {
+            "type": "object",
+            "properties": {
+                "time": {"type": "string", "format": "date-time"},
+                "temperature": {"type": "number"},
+                "enabled": {"anyOf": [{"type": "boolean"}, {"type": "null"}]},
+                "level": {"type": "string"},
+                "ip": {"type": "string"},
+                "history": {"anyOf": [{"type": "array", "items": {"type": "number"}}, {"type": "null"}]},
+                "levels": {"anyOf": [{"type": "object", "additionalProperties": {"type": "string"}}, {"type": "null"}]},
+                "overridden": {"type": "number"},
+                "nested": {"anyOf": [{"type": "object", "additionalProperties": {"type": "array", "items": {"type": "number"}}}, {"type": "null"}]}
+            },
+            "required": ["time", "temperature", "level", "ip", "history", "levels", "overridden", "nested"]
+        }
+    }
+}`, schema)
+}
+
+type Money struct {
+	Cents int64 `json:"cents"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100))
+}
+
+type Order struct {
+	Price Money `json:"price"`
+}
+
+func TestMarshalersRegistrationOrder(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Order{}).WithBackupDir("")
+	desiredResult := `export class Order {
+    price: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.price = source["price"];
+    }
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+
+	// Money's declaration wouldn't match its JSON, in any order:
+	expected := "typescriptify.Money is encoded as a JSON string by its custom marshaler, it can't be converted as a struct (set its TypeScript type with ManageType())"
+	_, err := New().Add(Money{}).Add(Order{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, expected)
+	_, err = New().Add(Order{}).Add(Money{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, expected)
+	_, err = New().Add(Money{}).Add(Order{}).WithBackupDir("").ConvertJSONSchema()
+	assert.EqualError(t, err, expected)
+}
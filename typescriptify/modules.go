@@ -0,0 +1,302 @@
+package typescriptify
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const indexModule = "index.ts"
+
+var exportedNameRegexp = regexp.MustCompile(`(?m)^export\s+(?:const\s+enum|class|interface|enum|type|const|function)\s+([A-Za-z_$][\w$]*)`)
+
+// declaration is the code of one converted struct, enum, union, type alias or constant (without the code of its
+// dependencies), and the names it references. Constants without a converted type have no type.
+type declaration struct {
+	typ        reflect.Type
+	code       string
+	references map[string]bool
+}
+
+// beginDeclaration starts collecting the names referenced by the declaration being converted, until `declare()`.
+// Declarations of dependencies are converted in between, so every one has its own set of names.
+func (t *TypeScriptify) beginDeclaration() {
+	t.references = append(t.references, map[string]bool{})
+}
+
+// endDeclaration stops collecting the names for the declaration being converted and returns them.
+func (t *TypeScriptify) endDeclaration() map[string]bool {
+	references := t.references[len(t.references)-1]
+	t.references = t.references[:len(t.references)-1]
+	return references
+}
+
+func (t *TypeScriptify) declare(typ reflect.Type, code string) string {
+	t.declarations = append(t.declarations, declaration{typ: typ, code: code, references: t.endDeclaration()})
+	return code
+}
+
+// reference records a name (of a type, schema or type guard) used in the declaration being converted, names declared
+// in other modules are imported by `ConvertFiles()`.
+func (t *TypeScriptify) reference(name string) string {
+	if n := len(t.references); n > 0 {
+		t.references[n-1][name] = true
+	}
+	return name
+}
+
+// typeRef returns the name of a type used in the declaration being converted.
+func (t *TypeScriptify) typeRef(typ reflect.Type) string {
+	return t.reference(t.typeName(typ))
+}
+
+// WithDirIndex creates an `index.ts` module re-exporting all modules created by `ConvertToDir()`.
+func (t *TypeScriptify) WithDirIndex(b bool) *TypeScriptify {
+	t.CreateDirIndex = b
+	return t
+}
+
+// ConvertFiles converts the registered types into one TypeScript module for every Golang package. The result maps
+// module file names (relative to the common parent package, with `/` separators) to their code, types used from
+// other modules are imported.
+func (t *TypeScriptify) ConvertFiles(customCode map[string]string) (map[string]string, error) {
+	if t.DontExport {
+		return nil, errors.New("types must be exported to be used in other modules")
+	}
+	if _, err := t.convert(customCode, true); err != nil {
+		return nil, err
+	}
+
+	var pkgPaths []string
+	untypedConsts := false
+	for _, decl := range t.declarations {
+		if decl.typ != nil {
+			pkgPaths = append(pkgPaths, t.pkgPath(decl.typ))
+		} else {
+			untypedConsts = true
+		}
+	}
+	moduleNames := moduleFileNames(pkgPaths)
+	for pkgPath, moduleName := range moduleNames {
+		if moduleName == t.ConstsModule && untypedConsts {
+			return nil, fmt.Errorf("module %s of package %s is also the module of constants without a converted type (see WithConstsModule())", t.ConstsModule, pkgPath)
+		}
+	}
+
+	code := map[string]string{}
+	references := map[string]map[string]bool{}
+	exports := map[string]string{}
+	typeExports := map[string]map[string]string{} // Name key => exported names => module
+	for _, decl := range t.declarations {
+		moduleName := t.ConstsModule
+		if decl.typ != nil {
+			moduleName = moduleNames[t.pkgPath(decl.typ)]
+		}
+		chunk := strings.Trim(decl.code, " "+t.Indent+"\r\n")
+		code[moduleName] += "\n" + chunk
+		if references[moduleName] == nil {
+			references[moduleName] = map[string]bool{}
+		}
+		for name := range decl.references {
+			references[moduleName][name] = true
+		}
+		for _, match := range exportedNameRegexp.FindAllStringSubmatch(chunk, -1) {
+			exports[match[1]] = moduleName
+			if decl.typ != nil {
+				key := t.nameKey(decl.typ)
+				if typeExports[key] == nil {
+					typeExports[key] = map[string]string{}
+				}
+				typeExports[key][match[1]] = moduleName
+			}
+		}
+	}
+
+	// Types from different packages can have the same name, but not if they're used in the same module:
+	moduleExports := map[string]map[string]string{}
+	for pkgPath, moduleName := range moduleNames {
+		moduleExports[moduleName] = map[string]string{}
+		for name, from := range exports {
+			moduleExports[moduleName][name] = from
+		}
+		for key := range t.moduleScopes[pkgPath] {
+			for name, from := range typeExports[key] {
+				moduleExports[moduleName][name] = from
+			}
+		}
+	}
+
+	result := map[string]string{}
+	for moduleName, body := range code {
+		header := ""
+		if t.CreateZodSchema {
+			header += "import { z } from \"zod\";\n"
+		}
+		for _, cimport := range t.customImports {
+			header += cimport + "\n"
+		}
+		visibleExports := exports
+		if e, found := moduleExports[moduleName]; found {
+			visibleExports = e
+		}
+		imports := moduleImports(moduleName, references[moduleName], visibleExports)
+		var importedModules []string
+		for importedModule := range imports {
+			importedModules = append(importedModules, importedModule)
+		}
+		sort.Strings(importedModules)
+		for _, importedModule := range importedModules {
+			header += fmt.Sprintf("import { %s } from '%s';\n", strings.Join(imports[importedModule], ", "), relativeImport(moduleName, importedModule))
+		}
+		result[moduleName] = header + body
+	}
+
+	if t.CreateDirIndex {
+		if _, found := result[indexModule]; found {
+			return nil, fmt.Errorf("module %s already exists", indexModule)
+		}
+		var moduleNames []string
+		for moduleName := range code {
+			moduleNames = append(moduleNames, moduleName)
+		}
+		sort.Strings(moduleNames)
+		index := ""
+		for _, moduleName := range moduleNames {
+			index += fmt.Sprintf("export * from '%s';\n", relativeImport(indexModule, moduleName))
+		}
+		result[indexModule] = index
+	}
+
+	return result, nil
+}
+
+// pkgPath returns the package of a type, anonymous structs are in the package of their parent struct.
+func (t *TypeScriptify) pkgPath(typ reflect.Type) string {
+	if parent, found := t.anonymousParents[typ]; found && typ.Name() == "" {
+		return t.pkgPath(parent.typ)
+	}
+	return typ.PkgPath()
+}
+
+// moduleImports returns the referenced names (grouped by module) which are declared in other modules.
+func moduleImports(moduleName string, references map[string]bool, exports map[string]string) map[string][]string {
+	imports := map[string][]string{}
+	for name := range references {
+		from, found := exports[name]
+		if !found || from == moduleName {
+			continue
+		}
+		imports[from] = append(imports[from], name)
+	}
+	for _, names := range imports {
+		sort.Strings(names)
+	}
+	return imports
+}
+
+// moduleFileNames returns the module file name for every package path. Names are relative to the common parent of
+// all packages, for example `github.com/org/app/models/billing` => `billing.ts`.
+func moduleFileNames(pkgPaths []string) map[string]string {
+	var common []string
+	for n, pkgPath := range pkgPaths {
+		parts := strings.Split(pkgPath, "/")
+		if n == 0 {
+			common = parts
+			continue
+		}
+		i := 0
+		for i < len(common) && i < len(parts) && common[i] == parts[i] {
+			i++
+		}
+		common = common[:i]
+	}
+	for _, pkgPath := range pkgPaths {
+		if len(strings.Split(pkgPath, "/")) == len(common) && len(common) > 0 {
+			// The parent is itself a package, it needs a module name too:
+			common = common[:len(common)-1]
+			break
+		}
+	}
+
+	result := map[string]string{}
+	for _, pkgPath := range pkgPaths {
+		parts := strings.Split(pkgPath, "/")
+		name := strings.Join(parts[len(common):], "/")
+		if name == "" {
+			name = "types"
+		}
+		result[pkgPath] = name + ".ts"
+	}
+	return result
+}
+
+// relativeImport returns the import path of a module relative to another module.
+func relativeImport(fromModule, toModule string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(fromModule)), filepath.FromSlash(strings.TrimSuffix(toModule, ".ts")))
+	if err != nil {
+		// Both are relative to the same directory, shouldn't happen:
+		panic(err.Error())
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// ConvertToDir writes one TypeScript module for every Golang package into the directory, see `ConvertFiles()`.
+func (t *TypeScriptify) ConvertToDir(dir string) error {
+	customCode := map[string]string{}
+	err := filepath.Walk(dir, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(fileName, ".ts") {
+			return nil
+		}
+		fileCustomCode, err := loadCustomCode(fileName)
+		if err != nil {
+			return err
+		}
+		for name, code := range fileCustomCode {
+			customCode[name] = code
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	converted, err := t.ConvertFiles(customCode)
+	if err != nil {
+		return err
+	}
+
+	for moduleName, code := range converted {
+		fileName := filepath.Join(dir, filepath.FromSlash(moduleName))
+		if err := os.MkdirAll(filepath.Dir(fileName), os.FileMode(0755)); err != nil {
+			return err
+		}
+		if len(t.BackupDir) > 0 {
+			if err := t.backup(fileName); err != nil {
+				return err
+			}
+		}
+		code = "/* Do not change, this code is generated from Golang structs */\n\n" + code
+		if err := ioutil.WriteFile(fileName, []byte(code), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package typescriptify
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/billing"
+)
+
+func TestConvertFiles(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(billing.Invoice{}).
+		WithInterface(true).
+		WithDirIndex(true).
+		WithBackupDir("")
+
+	files, err := converter.ConvertFiles(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"users.ts": `
+export interface Address {
+    street: string;
+    city: string;
+}
+export interface User {
+    name: string;
+    address: Address;
+}`,
+		"billing.ts": `import { Address, User } from './users';
+
+export interface InvoiceLine {
+    description: string;
+    amount: number;
+}
+export interface Invoice {
+    number: string;
+    customer: User;
+    lines: InvoiceLine[];
+    shipping?: Address;
+}`,
+		"index.ts": `export * from './billing';
+export * from './users';
+`,
+	}, files)
+}
+
+func TestConvertFilesImportsOnlyUsedNames(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(billing.Invoice{}).
+		WithInterface(true).
+		WithTypeGuards(true).
+		WithBackupDir("")
+
+	files, err := converter.ConvertFiles(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"users.ts": `
+export interface Address {
+    street: string;
+    city: string;
+}
+export function isAddress(x: unknown): x is Address {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["street"]
+        && "string" === typeof o["city"];
+}
+export interface User {
+    name: string;
+    address: Address;
+}
+export function isUser(x: unknown): x is User {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"]
+        && isAddress(o["address"]);
+}`,
+		"billing.ts": `import { Address, User, isAddress, isUser } from './users';
+
+export interface InvoiceLine {
+    description: string;
+    amount: number;
+}
+export function isInvoiceLine(x: unknown): x is InvoiceLine {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["description"]
+        && "number" === typeof o["amount"];
+}
+export interface Invoice {
+    number: string;
+    customer: User;
+    lines: InvoiceLine[];
+    shipping?: Address;
+}
+export function isInvoice(x: unknown): x is Invoice {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["number"]
+        && isUser(o["customer"])
+        && (o["lines"] === null || Array.isArray(o["lines"]) && o["lines"].every((v1: unknown) => isInvoiceLine(v1)))
+        && (o["shipping"] === undefined || o["shipping"] === null || isAddress(o["shipping"]));
+}`,
+	}, files)
+}
+
+type Memo struct {
+	Text string `json:"text" ts_doc:"see Address"`
+	Kind string `json:"kind" ts_type:"'User' | 'Invoice'"`
+}
+
+func TestConvertFilesDoesntImportNamesInDocsAndStrings(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(billing.Invoice{}).
+		Add(Memo{}).
+		WithInterface(true).
+		WithBackupDir("")
+
+	files, err := converter.ConvertFiles(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `
+export interface Memo {
+	/** see Address */
+    text: string;
+    kind: 'User' | 'Invoice';
+}`, files["typescriptify.ts"])
+}
+
+func TestConvertFilesImportsZodSchemas(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(billing.Invoice{}).
+		WithZodSchema(true).
+		WithBackupDir("")
+
+	files, err := converter.ConvertFiles(nil)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(files["billing.ts"], "import { z } from \"zod\";\nimport { AddressSchema, UserSchema } from './users';\n"), files["billing.ts"])
+}
+
+func TestConvertFilesWithoutExport(t *testing.T) {
+	t.Parallel()
+	converter := New().Add(billing.Invoice{}).WithBackupDir("")
+	converter.DontExport = true
+	_, err := converter.ConvertFiles(nil)
+	assert.EqualError(t, err, "types must be exported to be used in other modules")
+}
+
+func TestConvertFilesWithExistingIndex(t *testing.T) {
+	t.Parallel()
+	converter := New().Add(billing.Invoice{}).AddConst("MaxPageSize", 100).WithConstsModule("index.ts").WithDirIndex(true).WithBackupDir("")
+	_, err := converter.ConvertFiles(nil)
+	assert.EqualError(t, err, "module index.ts already exists")
+}
+
+func TestModuleFileNames(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, map[string]string{
+		"github.com/org/app/models": "models.ts",
+	}, moduleFileNames([]string{"github.com/org/app/models"}))
+	assert.Equal(t, map[string]string{
+		"github.com/org/app/models/billing": "billing.ts",
+		"github.com/org/app/models/users":   "users.ts",
+	}, moduleFileNames([]string{"github.com/org/app/models/billing", "github.com/org/app/models/users"}))
+	assert.Equal(t, map[string]string{
+		"github.com/org/app/models":         "models.ts",
+		"github.com/org/app/models/billing": "models/billing.ts",
+		"github.com/org/app/api/users":      "api/users.ts",
+	}, moduleFileNames([]string{"github.com/org/app/models", "github.com/org/app/models/billing", "github.com/org/app/api/users"}))
+}
+
+func TestRelativeImport(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "./users", relativeImport("billing.ts", "users.ts"))
+	assert.Equal(t, "./models/billing", relativeImport("models.ts", "models/billing.ts"))
+	assert.Equal(t, "../api/users", relativeImport("models/billing.ts", "api/users.ts"))
+	assert.Equal(t, "./users", relativeImport("api/billing.ts", "api/users.ts"))
+}
+
+func TestConvertToDir(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "typescriptify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	customCode := `/* Do not change, this code is generated from Golang structs */
+
+export class Address {
+    //[Address:]
+    toString(): string { return this.street; }
+
+    //[end]
+}`
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "users.ts"), []byte(customCode), 0644))
+
+	err = New().Add(billing.Invoice{}).WithDirIndex(true).WithBackupDir("").ConvertToDir(dir)
+	assert.Nil(t, err)
+
+	for _, fileName := range []string{"billing.ts", "users.ts", "index.ts"} {
+		byts, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(byts), "/* Do not change, this code is generated from Golang structs */\n\n"))
+	}
+	// The custom code is kept:
+	byts, err := ioutil.ReadFile(filepath.Join(dir, "users.ts"))
+	assert.Nil(t, err)
+	assert.Equal(t, `/* Do not change, this code is generated from Golang structs */
+
+
+export class Address {
+    street: string;
+    city: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.street = source["street"];
+        this.city = source["city"];
+    }
+    //[Address:]
+    toString(): string { return this.street; }
+
+    //[end]
+}
+export class User {
+    name: string;
+    address: Address;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.address = this.convertValues(source["address"], Address);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false, ...typeArgs: any[]): any {
+	    if (!a || !classs) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs, false, ...typeArgs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = this.convertValues(a[key], classs, false, ...typeArgs);
+	            }
+	            return a;
+	        }
+	        if (classs.variants) {
+	            return this.convertValues(a, classs.variants[a[classs.discriminator]], false, ...typeArgs);
+	        }
+	        return new classs(a, ...typeArgs);
+	    }
+	    return a;
+	}
+}`, string(byts))
+}
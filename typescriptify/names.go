@@ -0,0 +1,337 @@
+package typescriptify
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+)
+
+// NameCollisionStrategy defines how types from different packages with the same name are named.
+type NameCollisionStrategy int
+
+const (
+	// FailOnNameCollision makes the conversion fail with an error (default).
+	FailOnNameCollision NameCollisionStrategy = iota
+	// QualifyNameCollision prefixes the colliding names with their package names (`billing_Address`).
+	QualifyNameCollision
+	// CustomNameCollision names the colliding types with `TypeScriptify.CollisionNamer`.
+	CustomNameCollision
+)
+
+var (
+	majorVersionRegexp  = regexp.MustCompile(`^v[0-9]+$`)
+	nonIdentifierRegexp = regexp.MustCompile(`[^a-zA-Z0-9_$]+`)
+)
+
+// WithNameCollisions sets what happens when two types from different packages have the same name.
+func (t *TypeScriptify) WithNameCollisions(strategy NameCollisionStrategy) *TypeScriptify {
+	t.NameCollisions = strategy
+	return t
+}
+
+// WithCollisionNamer names types with colliding names with a custom function. The function returns the name without
+// prefix and suffix, for example:
+//
+//	converter.WithCollisionNamer(func(typ reflect.Type) string { return strings.ToUpper(path.Base(typ.PkgPath())) + typ.Name() })
+func (t *TypeScriptify) WithCollisionNamer(namer func(typ reflect.Type) string) *TypeScriptify {
+	t.NameCollisions = CustomNameCollision
+	t.CollisionNamer = namer
+	return t
+}
+
+// anonymousParent is the struct (and field) where an anonymous struct is first used.
+type anonymousParent struct {
+	typ   reflect.Type
+	field string
+}
+
+// nameKey identifies a TypeScript entity, all instantiations of a generic struct are one entity.
+func (t *TypeScriptify) nameKey(typ reflect.Type) string {
+	if typ.Name() == "" {
+		return typ.String()
+	}
+	if _, args := splitTypeArgs(typ.Name()); len(args) > 0 {
+		return genericKey(typ)
+	}
+	return typ.PkgPath() + "." + typ.Name()
+}
+
+// typeName returns the TypeScript name of a struct, enum or union (without type arguments).
+func (t *TypeScriptify) typeName(typ reflect.Type) string {
+	if name, found := t.names[t.nameKey(typ)]; found {
+		return t.Prefix + name + t.Suffix
+	}
+	name, _ := splitTypeArgs(typ.Name())
+	return t.Prefix + name + t.Suffix
+}
+
+// packageName returns the package name used to qualify type names, i.e. `billing` for `github.com/org/app/billing`.
+func packageName(pkgPath string) string {
+	name := path.Base(pkgPath)
+	if majorVersionRegexp.MatchString(name) && path.Dir(pkgPath) != "." {
+		name = path.Base(path.Dir(pkgPath))
+	}
+	return nonIdentifierRegexp.ReplaceAllString(name, "_")
+}
+
+// describeType returns the package and name of a type for errors, anonymous structs are described by their parent
+// field (like `anonymous struct github.com/org/app.Response.Meta`).
+func (t *TypeScriptify) describeType(typ reflect.Type) string {
+	if parent, found := t.anonymousParents[typ]; found && typ.Name() == "" {
+		return fmt.Sprintf("anonymous struct %s.%s", t.describeType(parent.typ), parent.field)
+	}
+	return typ.PkgPath() + "." + typ.Name()
+}
+
+// reachableTypes returns all structs, enums, unions and type aliases which will be converted. The parent struct (and field name) of
+// anonymous structs are saved in `t.anonymousParents`.
+func (t *TypeScriptify) reachableTypes() []reflect.Type {
+	t.anonymousParents = map[reflect.Type]anonymousParent{}
+
+	var result []reflect.Type
+	visited := map[reflect.Type]bool{}
+	var walk func(typ reflect.Type, parent anonymousParent)
+	walk = func(typ reflect.Type, parent anonymousParent) {
+		if typ.Kind() == reflect.Struct && typ.Name() == "" && parent.typ != nil {
+			if _, found := t.anonymousParents[typ]; !found {
+				t.anonymousParents[typ] = parent
+			}
+		}
+		if visited[typ] {
+			return
+		}
+		visited[typ] = true
+		if union, isUnion := t.unions[typ]; isUnion {
+			result = append(result, typ)
+			for _, variant := range union.Variants {
+				walk(variant.Type, anonymousParent{})
+			}
+			return
+		}
+		if _, isEnum := t.enums[typ]; isEnum || t.isPrimitiveAlias(typ) {
+			result = append(result, typ)
+			return
+		}
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			walk(typ.Elem(), parent)
+		case reflect.Map:
+			walk(typ.Key(), parent)
+			walk(typ.Elem(), parent)
+		case reflect.Struct:
+			result = append(result, typ)
+			for _, field := range t.typedFields(typ) {
+				walk(field.Type, anonymousParent{typ: typ, field: field.Name})
+			}
+		}
+	}
+
+	for _, enumTyp := range t.enumTypes {
+		walk(enumTyp.Type, anonymousParent{})
+	}
+	for _, unionTyp := range t.unionTypes {
+		walk(unionTyp, anonymousParent{})
+	}
+	for _, strctTyp := range t.structTypes {
+		walk(strctTyp.Type, anonymousParent{})
+	}
+	for _, c := range t.consts {
+		// Constants are typed only by enums and type aliases, other values (like structs) fail in `convertConst()`:
+		if _, err := tsLiteral(reflect.ValueOf(c.value)); err == nil {
+			walk(reflect.TypeOf(c.value), anonymousParent{})
+		}
+	}
+	return result
+}
+
+// typedFields returns the fields of a struct whose types are converted (not the ones with a custom TypeScript type),
+// pointers are dereferenced.
+func (t *TypeScriptify) typedFields(typ reflect.Type) []reflect.StructField {
+	var result []reflect.StructField
+	for _, field := range deepFields(typ) {
+		if isInlineMap(field) {
+			result = append(result, field)
+			continue
+		}
+		jsonFieldName := t.getJSONFieldName(field, false)
+		if len(jsonFieldName) == 0 {
+			continue
+		}
+		if field.Type.Kind() == reflect.Ptr {
+			field.Type = field.Type.Elem()
+		}
+		if opts := t.getFieldOptions(typ, field); opts.TSType != "" && !t.isAliasField(field.Type, opts) {
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// referencedTypes returns the converted types named in the declaration of a struct or union: the types of its fields
+// (or variants), with the type arguments of generic structs and the fields of inline anonymous structs.
+func (t *TypeScriptify) referencedTypes(typ reflect.Type) []reflect.Type {
+	var result []reflect.Type
+	visited := map[reflect.Type]bool{}
+	var walk func(typ reflect.Type)
+	walkFields := func(typ reflect.Type) {
+		if union, isUnion := t.unions[typ]; isUnion {
+			for _, variant := range union.Variants {
+				walk(variant.Type)
+			}
+			return
+		}
+		for _, field := range t.typedFields(typ) {
+			walk(field.Type)
+		}
+	}
+	walk = func(typ reflect.Type) {
+		if visited[typ] {
+			return
+		}
+		visited[typ] = true
+		if _, isUnion := t.unions[typ]; isUnion {
+			result = append(result, typ)
+			return
+		}
+		if _, isEnum := t.enums[typ]; isEnum || t.isPrimitiveAlias(typ) {
+			result = append(result, typ)
+			return
+		}
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			walk(typ.Elem())
+		case reflect.Map:
+			walk(typ.Key())
+			walk(typ.Elem())
+		case reflect.Struct:
+			inline := typ.Name() == "" && t.InlineAnonymous
+			if !inline {
+				result = append(result, typ)
+			}
+			if _, args := splitTypeArgs(typ.Name()); inline || len(args) > 0 {
+				walkFields(typ)
+			}
+		}
+	}
+	if typ.Kind() == reflect.Struct || t.unions[typ].Type != nil {
+		walkFields(typ)
+	}
+	return result
+}
+
+// resolveNames names all converted types. Types from different packages with the same name are renamed (or an error
+// is returned) depending on the name collision strategy.
+//
+// With modules (one for every package) only names used in the same module collide, i.e. the types declared in a package
+// and the types they reference. The index module re-exports all names, so with it names collide like in one file.
+func (t *TypeScriptify) resolveNames(modules bool) error {
+	t.names = map[string]string{}
+	t.moduleScopes = map[string]map[string]bool{}
+
+	var keys []string
+	types := map[string]reflect.Type{}
+	reachable := t.reachableTypes()
+	for _, typ := range reachable {
+		key := t.nameKey(typ)
+		if _, found := types[key]; found {
+			continue
+		}
+		name, _ := splitTypeArgs(typ.Name())
+		if name == "" {
+			// Anonymous structs are named after their parents, later
+			if !t.InlineAnonymous {
+				keys = append(keys, key)
+				types[key] = typ
+			}
+			continue
+		}
+		keys = append(keys, key)
+		types[key] = typ
+		t.names[key] = name
+	}
+
+	// The types named in every module (or just one scope for a single file):
+	scopes := map[string]map[string]bool{}
+	for _, typ := range reachable {
+		scope := ""
+		if modules && !t.CreateDirIndex {
+			scope = t.pkgPath(typ)
+		}
+		if scopes[scope] == nil {
+			scopes[scope] = map[string]bool{}
+		}
+		scopes[scope][t.nameKey(typ)] = true
+		for _, referenced := range t.referencedTypes(typ) {
+			scopes[scope][t.nameKey(referenced)] = true
+		}
+	}
+	if modules {
+		t.moduleScopes = scopes
+	}
+
+	// Two types with the same name in one scope:
+	collisions := map[string][2]string{}
+	for _, scope := range scopes {
+		scoped := map[string]string{}
+		for _, key := range keys {
+			if !scope[key] || types[key].Name() == "" {
+				continue
+			}
+			if other, found := scoped[t.names[key]]; found {
+				if _, found := collisions[t.names[key]]; !found {
+					collisions[t.names[key]] = [2]string{other, key}
+				}
+			}
+			scoped[t.names[key]] = key
+		}
+	}
+
+	for _, key := range keys {
+		if types[key].Name() == "" {
+			continue
+		}
+		colliding, found := collisions[t.names[key]]
+		if !found {
+			continue
+		}
+		switch t.NameCollisions {
+		case QualifyNameCollision:
+			t.names[key] = packageName(types[key].PkgPath()) + "_" + t.names[key]
+		case CustomNameCollision:
+			if t.CollisionNamer == nil {
+				return fmt.Errorf("no collision namer for %s (see WithCollisionNamer())", t.describeType(types[key]))
+			}
+			t.names[key] = t.CollisionNamer(types[key])
+		default:
+			return fmt.Errorf("name collision: %s and %s would both be named %s", t.describeType(types[colliding[0]]), t.describeType(types[colliding[1]]), t.names[key])
+		}
+	}
+
+	for _, key := range keys {
+		if types[key].Name() != "" {
+			continue
+		}
+		parent, found := t.anonymousParents[types[key]]
+		if !found {
+			return fmt.Errorf("cannot name anonymous struct %s", types[key].String())
+		}
+		t.names[key] = t.names[t.nameKey(parent.typ)] + "_" + parent.field
+	}
+
+	for _, scope := range scopes {
+		resolved := map[string]string{}
+		for _, key := range keys {
+			if !scope[key] {
+				continue
+			}
+			name := t.names[key]
+			if other, found := resolved[name]; found {
+				return fmt.Errorf("name collision: %s and %s are both named %s", t.describeType(types[other]), t.describeType(types[key]), name)
+			}
+			resolved[name] = key
+		}
+	}
+	return nil
+}
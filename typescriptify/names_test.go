@@ -0,0 +1,269 @@
+package typescriptify
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/billing"
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/returns"
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/shipping"
+)
+
+func TestNameCollision(t *testing.T) {
+	t.Parallel()
+	_, err := New().Add(shipping.Shipment{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "name collision: github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users.Address and github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/shipping.Address would both be named Address")
+}
+
+func TestNameCollisionQualified(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(shipping.Shipment{}).
+		WithNameCollisions(QualifyNameCollision).
+		WithInterface(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface shipping_Address {
+    line: string;
+    country: string;
+}
+export interface users_Address {
+    street: string;
+    city: string;
+}
+export interface User {
+    name: string;
+    address: users_Address;
+}
+export interface Shipment {
+    recipient: User;
+    from: shipping_Address;
+    to: shipping_Address[];
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestNameCollisionQualifiedClasses(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(shipping.Shipment{}).
+		WithNameCollisions(QualifyNameCollision).
+		WithPrefix("API").
+		WithBackupDir("")
+
+	desiredResult := `export class APIshipping_Address {
+    line: string;
+    country: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.line = source["line"];
+        this.country = source["country"];
+    }
+}
+export class APIusers_Address {
+    street: string;
+    city: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.street = source["street"];
+        this.city = source["city"];
+    }
+}
+export class APIUser {
+    name: string;
+    address: APIusers_Address;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.address = this.convertValues(source["address"], APIusers_Address);
+    }
+
+	` + tsConvertValuesFunc + `
+}
+export class APIShipment {
+    recipient: APIUser;
+    from: APIshipping_Address;
+    to: APIshipping_Address[];
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.recipient = this.convertValues(source["recipient"], APIUser);
+        this.from = this.convertValues(source["from"], APIshipping_Address);
+        this.to = this.convertValues(source["to"], APIshipping_Address);
+    }
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := `{"recipient": {"name": "J", "address": {"street": "S", "city": "C"}}, "from": {"line": "L", "country": "HR"}, "to": [{"line": "L2", "country": "SI"}]}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new APIShipment(` + jsn + `).from instanceof APIshipping_Address`,
+		`new APIShipment(` + jsn + `).to[0] instanceof APIshipping_Address`,
+		`new APIShipment(` + jsn + `).recipient.address instanceof APIusers_Address`,
+	})
+}
+
+func TestNameCollisionCustomNamer(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(shipping.Shipment{}).
+		WithCollisionNamer(func(typ reflect.Type) string {
+			pkg := path.Base(typ.PkgPath())
+			return strings.ToUpper(pkg[:1]) + pkg[1:] + typ.Name()
+		}).
+		WithInterface(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface ShippingAddress {
+    line: string;
+    country: string;
+}
+export interface UsersAddress {
+    street: string;
+    city: string;
+}
+export interface User {
+    name: string;
+    address: UsersAddress;
+}
+export interface Shipment {
+    recipient: User;
+    from: ShippingAddress;
+    to: ShippingAddress[];
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestNameCollisionCustomNamerStillColliding(t *testing.T) {
+	t.Parallel()
+	_, err := New().
+		Add(shipping.Shipment{}).
+		WithCollisionNamer(func(typ reflect.Type) string { return "User" }).
+		WithBackupDir("").
+		Convert(nil)
+	assert.EqualError(t, err, "name collision: github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users.User and github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users.Address are both named User")
+}
+
+func TestNameCollisionWithoutCustomNamer(t *testing.T) {
+	t.Parallel()
+	_, err := New().Add(shipping.Shipment{}).WithNameCollisions(CustomNameCollision).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "no collision namer for github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users.Address (see WithCollisionNamer())")
+}
+
+func TestNameCollisionInModules(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(billing.Invoice{}).
+		Add(shipping.Shipment{}).
+		WithInterface(true).
+		WithBackupDir("")
+
+	// Both addresses are named `Address`, no module uses both:
+	files, err := converter.ConvertFiles(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"users.ts": `
+export interface Address {
+    street: string;
+    city: string;
+}
+export interface User {
+    name: string;
+    address: Address;
+}`,
+		"billing.ts": `import { Address, User } from './users';
+
+export interface InvoiceLine {
+    description: string;
+    amount: number;
+}
+export interface Invoice {
+    number: string;
+    customer: User;
+    lines: InvoiceLine[];
+    shipping?: Address;
+}`,
+		"shipping.ts": `import { User } from './users';
+
+export interface Address {
+    line: string;
+    country: string;
+}
+export interface Shipment {
+    recipient: User;
+    from: Address;
+    to: Address[];
+}`,
+	}, files)
+}
+
+func TestNameCollisionInOneModule(t *testing.T) {
+	t.Parallel()
+	_, err := New().Add(returns.Return{}).WithBackupDir("").ConvertFiles(nil)
+	assert.EqualError(t, err, "name collision: github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/shipping.Address and github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users.Address would both be named Address")
+
+	// The index module re-exports the names of all modules:
+	_, err = New().Add(billing.Invoice{}).Add(shipping.Shipment{}).WithDirIndex(true).WithBackupDir("").ConvertFiles(nil)
+	assert.EqualError(t, err, "name collision: github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users.Address and github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/shipping.Address would both be named Address")
+}
+
+func TestNameCollisionInOneModuleQualified(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(returns.Return{}).
+		Add(billing.Invoice{}).
+		WithNameCollisions(QualifyNameCollision).
+		WithInterface(true).
+		WithBackupDir("")
+
+	files, err := converter.ConvertFiles(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"shipping.ts": `
+export interface shipping_Address {
+    line: string;
+    country: string;
+}`,
+		"users.ts": `
+export interface users_Address {
+    street: string;
+    city: string;
+}
+export interface User {
+    name: string;
+    address: users_Address;
+}`,
+		"returns.ts": `import { shipping_Address } from './shipping';
+import { users_Address } from './users';
+
+export interface Return {
+    pickup: shipping_Address;
+    customer: users_Address;
+}`,
+		"billing.ts": `import { User, users_Address } from './users';
+
+export interface InvoiceLine {
+    description: string;
+    amount: number;
+}
+export interface Invoice {
+    number: string;
+    customer: User;
+    lines: InvoiceLine[];
+    shipping?: users_Address;
+}`,
+	}, files)
+}
+
+func TestPackageName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "billing", packageName("github.com/org/app/billing"))
+	assert.Equal(t, "billing", packageName("github.com/org/billing/v2"))
+	assert.Equal(t, "go_models", packageName("github.com/org/go-models"))
+}
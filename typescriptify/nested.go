@@ -0,0 +1,124 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// containerElem returns the innermost element type of (nested) pointers, slices, arrays and maps.
+func containerElem(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// convertValuesTarget returns the class (and the functions converting its type arguments) if values of the type can be
+// converted with a single `convertValues()` call: a struct or union, or (nested) slices of them, optionally in a map. It
+// handles only one level of maps, deeper maps and values of type parameters are converted by `valueInitializer()`.
+func (t *TypeScriptify) convertValuesTarget(g *genericStruct, types []reflect.Type, depth int) (class string, classArgs []string, asMap bool, ok bool) {
+	elems, canBeMap := types, true
+	for ; g.paramIndex(elems) < 0; elems = mapTypes(elems, reflect.Type.Elem) {
+		switch elems[0].Kind() {
+		case reflect.Ptr:
+			continue
+		case reflect.Map:
+			if !canBeMap {
+				return "", nil, false, false
+			}
+			asMap = true
+		case reflect.Slice, reflect.Array:
+		default:
+			elem := elems[0]
+			if t.isManaged(elem) {
+				return "", nil, false, false
+			}
+			if _, isUnion := t.unions[elem]; isUnion {
+				return t.typeRef(elem), nil, asMap, true
+			}
+			if _, isEnum := t.enums[elem]; isEnum || elem.Kind() != reflect.Struct || t.isInlineStruct(elem) {
+				return "", nil, false, false
+			}
+			return t.reference(t.structName(elem)), t.typeArgConverters(g, elems, depth), asMap, true
+		}
+		canBeMap = false
+	}
+	return "", nil, false, false
+}
+
+// isManaged checks if the type has a custom TypeScript type (`ManageType()`) or a custom JSON encoding, its values
+// aren't converted to classes.
+func (t *TypeScriptify) isManaged(typ reflect.Type) bool {
+	if opts, found := t.typeOptions(typ); found && opts.TSType != "" {
+		return true
+	}
+	_, is := t.marshalers[typ]
+	return is
+}
+
+// valueInitializer returns the constructor expression converting the nested values of a type at any depth: structs
+// and unions to class instances, inline anonymous structs field by field, values of managed types with their
+// `TSTransform`, and values of type parameters with the functions passed to the constructor. types contains the type
+// in every instantiation of the generic struct g being declared, g is nil outside of generic declarations. If nothing
+// needs to be converted, it returns source.
+func (t *TypeScriptify) valueInitializer(g *genericStruct, types []reflect.Type, source string, depth int) string {
+	if n := g.paramIndex(types); n >= 0 {
+		return fmt.Sprintf("convert%s(%s)", g.params[n], source)
+	}
+	typ := types[0]
+	if opts, found := t.typeOptions(typ); found && opts.TSTransform != "" {
+		return strings.Replace(opts.TSTransform, "__VALUE__", source, -1)
+	}
+	if t.isManaged(typ) {
+		return source
+	}
+
+	v := fmt.Sprintf("v%d", depth)
+	switch {
+	case typ.Kind() == reflect.Ptr:
+		return t.valueInitializer(g, mapTypes(types, reflect.Type.Elem), source, depth)
+	case t.isInlineStruct(typ):
+		var values []string
+		for _, fld := range t.structFields(typ) {
+			name := strings.ReplaceAll(fld.name, "?", "")
+			value := fmt.Sprintf(`%s["%s"]`, v, name)
+			var initializer string
+			if fld.opts.TSTransform != "" {
+				initializer = strings.Replace(fld.opts.TSTransform, "__VALUE__", value, -1)
+			} else if fld.opts.TSType == "" {
+				initializer = t.valueInitializer(nil, fld.types, value, depth+1)
+			}
+			if initializer != "" && initializer != value {
+				values = append(values, fmt.Sprintf("%q: %s", name, initializer))
+			}
+		}
+		if len(values) == 0 {
+			return source
+		}
+		return fmt.Sprintf("((%s: any) => %s == null ? %s : {...%s, %s})(%s)", v, v, v, v, strings.Join(values, ", "), source)
+	case !t.hasInlineStruct(typ):
+		if class, classArgs, asMap, ok := t.convertValuesTarget(g, types, depth); ok {
+			// Structs and unions (also in slices and a map) are converted by convertValues():
+			return convertValuesCall(source, class, asMap, classArgs)
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		elem := fmt.Sprintf("e%d", depth)
+		initializer := t.valueInitializer(g, mapTypes(types, reflect.Type.Elem), elem, depth+1)
+		if initializer == elem {
+			return source
+		}
+		return fmt.Sprintf("((%s: any) => %s == null ? %s : (%s as any[]).map((%s: any) => %s))(%s)", v, v, v, v, elem, initializer, source)
+	case reflect.Map:
+		key := fmt.Sprintf("k%d", depth)
+		initializer := t.valueInitializer(g, mapTypes(types, reflect.Type.Elem), fmt.Sprintf("%s[%s]", v, key), depth+1)
+		if initializer == fmt.Sprintf("%s[%s]", v, key) {
+			return source
+		}
+		return fmt.Sprintf("((%s: any) => { if (%s == null) return %s; for (const %s of Object.keys(%s)) %s[%s] = %s; return %s; })(%s)", v, v, v, key, v, v, key, initializer, v, source)
+	}
+	return source
+}
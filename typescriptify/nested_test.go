@@ -0,0 +1,212 @@
+package typescriptify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Item struct {
+	Name string `json:"name"`
+}
+
+type Nested struct {
+	Grouped map[string][]*Item         `json:"grouped"`
+	Matrix  map[string]map[string]int  `json:"matrix"`
+	Deep    map[string]map[string]Item `json:"deep"`
+	Rows    []map[string]*Item         `json:"rows"`
+	Grid    [][]*Item                  `json:"grid"`
+	Days    []Weekday                  `json:"days"`
+	Times   map[string][]time.Time     `json:"times"`
+}
+
+func TestNestedContainers(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		WithPrefix("API").
+		AddEnum(allWeekdaysV1).
+		Add(Nested{}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)"}).
+		WithBackupDir("")
+
+	desiredResult := `export enum APIWeekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export class APIItem {
+    name: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+    }
+}
+export class APINested {
+    grouped: {[key: string]: APIItem[]};
+    matrix: {[key: string]: {[key: string]: number}};
+    deep: {[key: string]: {[key: string]: APIItem}};
+    rows: {[key: string]: APIItem}[];
+    grid: APIItem[][];
+    days: APIWeekday[];
+    times: {[key: string]: Date[]};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.grouped = this.convertValues(source["grouped"], APIItem, true);
+        this.matrix = source["matrix"];
+        this.deep = ((v1: any) => { if (v1 == null) return v1; for (const k1 of Object.keys(v1)) v1[k1] = this.convertValues(v1[k1], APIItem, true); return v1; })(source["deep"]);
+        this.rows = ((v1: any) => v1 == null ? v1 : (v1 as any[]).map((e1: any) => this.convertValues(e1, APIItem, true)))(source["rows"]);
+        this.grid = this.convertValues(source["grid"], APIItem);
+        this.days = source["days"];
+        this.times = ((v1: any) => { if (v1 == null) return v1; for (const k1 of Object.keys(v1)) v1[k1] = ((v2: any) => v2 == null ? v2 : (v2 as any[]).map((e2: any) => new Date(e2)))(v1[k1]); return v1; })(source["times"]);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false, ...typeArgs: any[]): any {
+	    if (!a || !classs) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs, false, ...typeArgs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = this.convertValues(a[key], classs, false, ...typeArgs);
+	            }
+	            return a;
+	        }
+	        if (classs.variants) {
+	            return this.convertValues(a, classs.variants[a[classs.discriminator]], false, ...typeArgs);
+	        }
+	        return new classs(a, ...typeArgs);
+	    }
+	    return a;
+	}
+}`
+	source := `{"grouped": {"a": [{"name": "g"}]}, "matrix": {"a": {"b": 1}}, "deep": {"a": {"b": {"name": "d"}}}, "rows": [{"a": {"name": "r"}}], "grid": [[{"name": "x"}]], "days": [1], "times": {"a": ["2024-01-02T03:04:05Z"]}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new APINested(` + source + `).grouped["a"][0] instanceof APIItem`,
+		`new APINested(` + source + `).grouped["a"][0].name == "g"`,
+		`new APINested(` + source + `).matrix["a"]["b"] == 1`,
+		`new APINested(` + source + `).deep["a"]["b"] instanceof APIItem`,
+		`new APINested(` + source + `).rows[0]["a"] instanceof APIItem`,
+		`new APINested(` + source + `).grid[0][0] instanceof APIItem`,
+		`new APINested(` + source + `).days[0] == APIWeekday.MONDAY`,
+		`new APINested(` + source + `).times["a"][0] instanceof Date`,
+		`new APINested(` + source + `).times["a"][0].getUTCFullYear() == 2024`,
+	})
+}
+
+func TestNestedContainersTypeGuards(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddEnum(allWeekdaysV1).
+		Add(Nested{}).
+		ManageType(time.Time{}, TypeOptions{TSType: "string"}).
+		WithInterface(true).
+		WithTypeGuards(true).
+		WithBackupDir("")
+
+	desiredResult := `export enum Weekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export function isWeekday(x: unknown): x is Weekday {
+    return ([Weekday.SUNDAY, Weekday.MONDAY, Weekday.TUESDAY, Weekday.WEDNESDAY, Weekday.THURSDAY, Weekday.FRIDAY, Weekday.SATURDAY] as unknown[]).includes(x);
+}
+export interface Item {
+    name: string;
+}
+export function isItem(x: unknown): x is Item {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"];
+}
+export interface Nested {
+    grouped: {[key: string]: Item[]};
+    matrix: {[key: string]: {[key: string]: number}};
+    deep: {[key: string]: {[key: string]: Item}};
+    rows: {[key: string]: Item}[];
+    grid: Item[][];
+    days: Weekday[];
+    times: {[key: string]: string[]};
+}
+export function isNested(x: unknown): x is Nested {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return (o["grouped"] === null || "object" === typeof o["grouped"] && o["grouped"] !== null && !Array.isArray(o["grouped"]) && Object.values(o["grouped"]).every((v1: unknown) => Array.isArray(v1) && v1.every((v2: unknown) => isItem(v2))))
+        && (o["matrix"] === null || "object" === typeof o["matrix"] && o["matrix"] !== null && !Array.isArray(o["matrix"]) && Object.values(o["matrix"]).every((v1: unknown) => "object" === typeof v1 && v1 !== null && !Array.isArray(v1) && Object.values(v1).every((v2: unknown) => "number" === typeof v2)))
+        && (o["deep"] === null || "object" === typeof o["deep"] && o["deep"] !== null && !Array.isArray(o["deep"]) && Object.values(o["deep"]).every((v1: unknown) => "object" === typeof v1 && v1 !== null && !Array.isArray(v1) && Object.values(v1).every((v2: unknown) => isItem(v2))))
+        && (o["rows"] === null || Array.isArray(o["rows"]) && o["rows"].every((v1: unknown) => "object" === typeof v1 && v1 !== null && !Array.isArray(v1) && Object.values(v1).every((v2: unknown) => isItem(v2))))
+        && (o["grid"] === null || Array.isArray(o["grid"]) && o["grid"].every((v1: unknown) => Array.isArray(v1) && v1.every((v2: unknown) => isItem(v2))))
+        && (o["days"] === null || Array.isArray(o["days"]) && o["days"].every((v1: unknown) => isWeekday(v1)))
+        && (o["times"] === null || "object" === typeof o["times"] && o["times"] !== null && !Array.isArray(o["times"]) && Object.values(o["times"]).every((v1: unknown) => Array.isArray(v1)));
+}`
+	source := `{"grouped": {"a": [{"name": "g"}]}, "matrix": {"a": {"b": 1}}, "deep": {"a": {"b": {"name": "d"}}}, "rows": [{"a": {"name": "r"}}], "grid": [[{"name": "x"}]], "days": [1], "times": {"a": ["2024-01-02T03:04:05Z"]}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`isNested(` + source + `)`,
+		`!isNested({...` + source + `, "grouped": {"a": [{"name": 1}]}})`,
+		`!isNested({...` + source + `, "matrix": {"a": {"b": "1"}}})`,
+		`!isNested({...` + source + `, "rows": [{"a": null}]})`,
+		`!isNested({...` + source + `, "grid": [{"name": "x"}]})`,
+		`!isNested({...` + source + `, "days": [7]})`,
+		`!isNested({...` + source + `, "times": {"a": "2024-01-02T03:04:05Z"}})`,
+	})
+}
+
+type Schedule struct {
+	Slots map[string][][]time.Time `json:"slots"`
+}
+
+func TestNestedContainersSerialization(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Schedule{}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)", TSSerialize: "__VALUE__.toISOString()"}).
+		WithBackupDir("")
+
+	desiredResult := `export class Schedule {
+    slots: {[key: string]: Date[][]};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.slots = ((v1: any) => { if (v1 == null) return v1; for (const k1 of Object.keys(v1)) v1[k1] = ((v2: any) => v2 == null ? v2 : (v2 as any[]).map((e2: any) => ((v3: any) => v3 == null ? v3 : (v3 as any[]).map((e3: any) => new Date(e3)))(e2)))(v1[k1]); return v1; })(source["slots"]);
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "slots": ((v1: any) => { if (v1 == null) return v1; const r1: any = {}; for (const k1 of Object.keys(v1)) r1[k1] = ((v2: any) => v2 == null ? v2 : (v2 as any[]).map((e2: any) => ((v3: any) => v3 == null ? v3 : (v3 as any[]).map((e3: any) => e3.toISOString()))(e2)))(v1[k1]); return r1; })(this.slots),
+        };
+    }
+}`
+	source := `{"slots": {"a": [["2024-01-02T03:04:05.000Z"], []], "b": null}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Schedule(` + source + `).slots["a"][0][0] instanceof Date`,
+		`new Schedule(` + source + `).slots["b"] === null`,
+		`JSON.stringify(new Schedule(` + source + `)) === JSON.stringify(` + source + `)`,
+	})
+}
+
+func TestNestedContainersWithInvalidMapKey(t *testing.T) {
+	t.Parallel()
+	type BadKeys struct {
+		Rows []map[string]map[bool]int `json:"rows"`
+	}
+	_, err := New().Add(BadKeys{}).WithBackupDir("").Convert(nil)
+	assert.EqualError(t, err, "map key type bool can't be encoded to JSON")
+}
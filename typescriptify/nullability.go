@@ -0,0 +1,58 @@
+package typescriptify
+
+import (
+	"reflect"
+)
+
+// NullabilityMode defines how fields which can be encoded as `null` (pointers, slices and maps) are converted.
+type NullabilityMode int
+
+const (
+	// NullAsOptional converts pointers to optional fields (`field?: T`), slices and maps are not optional (default).
+	NullAsOptional NullabilityMode = iota
+	// NullAsNullable converts pointers, slices and maps to nullable fields (`field: T | null`).
+	NullAsNullable
+	// NullAsOptionalNullable converts pointers, slices and maps to optional nullable fields (`field?: T | null`).
+	NullAsOptionalNullable
+)
+
+// WithNullability sets how pointers, slices and maps (which are encoded as `null` if nil) are converted. Fields with
+// `omitempty` or `omitzero` are never `null` (nil values are omitted), they are optional in every mode.
+func (t *TypeScriptify) WithNullability(mode NullabilityMode) *TypeScriptify {
+	t.Nullability = mode
+	return t
+}
+
+// canBeNull checks if a field (with its pointer already dereferenced if isPtr) is encoded as `null` if nil.
+func (t *TypeScriptify) canBeNull(field reflect.StructField, isPtr bool) bool {
+	if parseJSONTag(field.Tag.Get("json")).optional() {
+		return false
+	}
+	if isPtr {
+		return true
+	}
+	if _, is := t.marshalers[field.Type]; is {
+		// Nil slices and maps with a custom encoding are encoded by their MarshalJSON()/MarshalText()
+		return false
+	}
+	return field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Map
+}
+
+// isOptional checks if a field (with its pointer already dereferenced if isPtr) is converted to an optional field.
+func (t *TypeScriptify) isOptional(field reflect.StructField, isPtr bool) bool {
+	if parseJSONTag(field.Tag.Get("json")).optional() {
+		return true
+	}
+	switch t.Nullability {
+	case NullAsNullable:
+		return false
+	case NullAsOptionalNullable:
+		return t.canBeNull(field, isPtr)
+	}
+	return isPtr
+}
+
+// isNullable checks if a field (with its pointer already dereferenced if isPtr) is converted to `T | null`.
+func (t *TypeScriptify) isNullable(field reflect.StructField, isPtr bool) bool {
+	return t.Nullability != NullAsOptional && t.canBeNull(field, isPtr)
+}
@@ -0,0 +1,188 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Profile struct {
+	Name     string            `json:"name"`
+	Address  *Address          `json:"address"`
+	Nickname *string           `json:"nickname,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Aliases  []string          `json:"aliases,omitzero"`
+}
+
+func TestNullabilityModes(t *testing.T) {
+	t.Parallel()
+
+	// Nil pointers, slices and maps are null, unless they are omitted:
+	byts, err := json.Marshal(Profile{})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"","address":null,"tags":null,"labels":null}`, string(byts))
+
+	for _, data := range []struct {
+		mode     NullabilityMode
+		expected string
+	}{
+		{NullAsOptional, `export interface Profile {
+    name: string;
+    address?: Address;
+    nickname?: string;
+    tags: string[];
+    labels: {[key: string]: string};
+    aliases?: string[];
+}`},
+		{NullAsNullable, `export interface Profile {
+    name: string;
+    address: Address | null;
+    nickname?: string;
+    tags: string[] | null;
+    labels: {[key: string]: string} | null;
+    aliases?: string[];
+}`},
+		{NullAsOptionalNullable, `export interface Profile {
+    name: string;
+    address?: Address | null;
+    nickname?: string;
+    tags?: string[] | null;
+    labels?: {[key: string]: string} | null;
+    aliases?: string[];
+}`},
+	} {
+		converter := New().Add(Profile{}).WithInterface(true).WithNullability(data.mode).WithBackupDir("")
+		desiredResult := `export interface Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+}
+` + data.expected
+		testConverter(t, converter, true, desiredResult, nil)
+	}
+}
+
+func TestNullableClass(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Profile{}).
+		WithNullability(NullAsNullable).
+		WithTypeGuards(true).
+		WithBackupDir("")
+
+	desiredResult := `export class Address {
+    duration: number;
+    text?: string;
+    Text2?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.duration = source["duration"];
+        this.text = source["text"];
+        this.Text2 = source["Text2"];
+    }
+}
+export function isAddress(x: unknown): x is Address {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "number" === typeof o["duration"]
+        && (o["text"] === undefined || "string" === typeof o["text"])
+        && (o["Text2"] === undefined || "string" === typeof o["Text2"]);
+}
+export class Profile {
+    name: string;
+    address: Address | null;
+    nickname?: string;
+    tags: string[] | null;
+    labels: {[key: string]: string} | null;
+    aliases?: string[];
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.address = this.convertValues(source["address"], Address);
+        this.nickname = source["nickname"];
+        this.tags = source["tags"];
+        this.labels = source["labels"];
+        this.aliases = source["aliases"];
+    }
+
+	` + tsConvertValuesFunc + `
+}
+export function isProfile(x: unknown): x is Profile {
+    if ("object" !== typeof x || x === null) {
+        return false;
+    }
+    const o = x as any;
+    return "string" === typeof o["name"]
+        && (o["address"] === null || isAddress(o["address"]))
+        && (o["nickname"] === undefined || "string" === typeof o["nickname"])
+        && (o["tags"] === null || Array.isArray(o["tags"]) && o["tags"].every((v1: unknown) => "string" === typeof v1))
+        && (o["labels"] === null || "object" === typeof o["labels"] && o["labels"] !== null && !Array.isArray(o["labels"]) && Object.values(o["labels"]).every((v1: unknown) => "string" === typeof v1))
+        && (o["aliases"] === undefined || Array.isArray(o["aliases"]) && o["aliases"].every((v1: unknown) => "string" === typeof v1));
+}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Profile({"name": "a", "address": null, "tags": null, "labels": null}).address === null`,
+		`new Profile({"name": "a", "address": {"duration": 1}, "tags": null, "labels": null}).address instanceof Address`,
+		`isProfile({"name": "a", "address": null, "tags": null, "labels": null})`,
+		`isProfile({"name": "a", "address": null, "tags": ["x"], "labels": {"a": "b"}, "nickname": "n"})`,
+		`!isProfile({"name": "a", "address": null, "tags": null, "labels": null, "nickname": null})`,
+	})
+}
+
+func TestNullableZodAndJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(Profile{}).WithZodSchema(true).WithNullability(NullAsOptionalNullable).WithBackupDir("")
+	desiredResult := `import { z } from "zod";
+
+export const AddressSchema = z.object({
+    duration: z.number(),
+    text: z.string().optional(),
+    Text2: z.string().optional(),
+});
+export type Address = z.infer<typeof AddressSchema>;
+export const ProfileSchema = z.object({
+    name: z.string(),
+    address: AddressSchema.nullable().optional(),
+    nickname: z.string().optional(),
+    tags: z.array(z.string()).nullable().optional(),
+    labels: z.record(z.string(), z.string()).nullable().optional(),
+    aliases: z.array(z.string()).optional(),
+});
+export type Profile = z.infer<typeof ProfileSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+
+	schema, err := New().Add(Profile{}).WithNullability(NullAsNullable).WithBackupDir("").ConvertJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "$defs": {
+        "Address": {
+            "type": "object",
+            "properties": {
+                "duration": {"type": "number"},
+                "text": {"type": "string"},
+                "Text2": {"type": "string"}
+            },
+            "required": ["duration"]
+        },
+        "Profile": {
+            "type": "object",
+            "properties": {
+                "name": {"type": "string"},
+                "address": {"anyOf": [{"$ref": "#/$defs/Address"}, {"type": "null"}]},
+                "nickname": {"type": "string"},
+                "tags": {"anyOf": [{"type": "array", "items": {"type": "string"}}, {"type": "null"}]},
+                "labels": {"anyOf": [{"type": "object", "additionalProperties": {"type": "string"}}, {"type": "null"}]},
+                "aliases": {"type": "array", "items": {"type": "string"}}
+            },
+            "required": ["name", "address", "tags", "labels"]
+        }
+    }
+}`, schema)
+}
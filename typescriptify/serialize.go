@@ -0,0 +1,156 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// nullGuard prefixes the builtin transform and serialize expressions, nil values are kept as they are.
+	nullGuard = "__VALUE__ == null ? __VALUE__ : "
+
+	tsSerializeTag        = "ts_serialize"
+	tsSerializeValuesFunc = `serializeValues(a: any): any {
+	if (!a) {
+		return a;
+	}
+	if (Array.isArray(a)) {
+		return a.map(elem => this.serializeValues(elem));
+	} else if ("function" === typeof a.toJSON) {
+		return a.toJSON();
+	} else if ("object" === typeof a) {
+		const result: any = {};
+		for (const key of Object.keys(a)) {
+			result[key] = this.serializeValues(a[key]);
+		}
+		return result;
+	}
+	return a;
+}`
+)
+
+// needsSerialization checks if values of a type need a custom serialization, i.e. if the type contains a struct with
+// a `ts_serialize` field (directly or in a nested struct, slice, map or union variant).
+func (t *TypeScriptify) needsSerialization(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	if opts, found := t.typeOptions(typ); found {
+		return opts.TSSerialize != ""
+	}
+	if union, isUnion := t.unions[typ]; isUnion {
+		for _, variant := range union.Variants {
+			if t.needsSerialization(variant.Type, visited) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return t.needsSerialization(typ.Elem(), visited)
+	case reflect.Struct:
+		if visited[typ] {
+			return false
+		}
+		visited[typ] = true
+		for _, field := range deepFields(typ) {
+			if field.Type.Kind() == reflect.Ptr {
+				field.Type = field.Type.Elem()
+			}
+			jsonFieldName := t.getJSONFieldName(field, false)
+			if len(jsonFieldName) == 0 {
+				continue
+			}
+			opts := t.getFieldOptions(typ, field)
+			if opts.TSSerialize != "" {
+				return true
+			}
+			if opts.TSType == "" && t.needsSerialization(field.Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toJSONMethod returns the `toJSON()` method (and if the `serializeValues()` helper is needed) for a class. Fields
+// with a `ts_serialize` expression are serialized with it, fields with nested classes which need a custom
+// serialization are serialized with `serializeValues()`.
+func (t *TypeScriptify) toJSONMethod(bases []reflect.Type, fields []fieldInfo) (method string, needsSerializeValues bool) {
+	var lines []string
+	for _, fld := range fields {
+		name := strings.ReplaceAll(fld.name, "?", "")
+		value := tsPropertyAccess("this", name)
+		var expression string
+		if fld.opts.TSSerialize != "" {
+			expression = strings.Replace(fld.opts.TSSerialize, "__VALUE__", value, -1)
+			if (strings.HasSuffix(fld.name, "?") || fld.nullable) && !strings.HasPrefix(fld.opts.TSSerialize, nullGuard) {
+				expression = strings.Replace(nullGuard, "__VALUE__", value, -1) + expression
+			}
+		} else if serializer := t.valueSerializer(fld.types[0], value, 1); fld.opts.TSType == "" && len(fld.types) == 1 && serializer != value {
+			expression = serializer
+		} else if fld.opts.TSType == "" {
+			for _, typ := range fld.types {
+				if t.needsSerialization(typ, map[reflect.Type]bool{}) {
+					expression = fmt.Sprintf("this.serializeValues(%s)", value)
+					needsSerializeValues = true
+					break
+				}
+			}
+		}
+		if expression != "" {
+			lines = append(lines, fmt.Sprintf("%s%s%s%q: %s,", t.Indent, t.Indent, t.Indent, name, expression))
+		}
+	}
+
+	if len(lines) == 0 {
+		// Nothing to serialize (or inherited from the base class)
+		return "", false
+	}
+	spread := "...this"
+	if len(bases) > 0 && t.needsSerialization(bases[0], map[reflect.Type]bool{}) {
+		spread = "...super.toJSON()"
+	}
+
+	method = fmt.Sprintf("%stoJSON(): any {\n", t.Indent)
+	method += fmt.Sprintf("%s%sreturn {\n", t.Indent, t.Indent)
+	method += fmt.Sprintf("%s%s%s%s,\n", t.Indent, t.Indent, t.Indent, spread)
+	for _, line := range lines {
+		method += line + "\n"
+	}
+	method += fmt.Sprintf("%s%s};\n", t.Indent, t.Indent)
+	method += fmt.Sprintf("%s}\n", t.Indent)
+	return method, needsSerializeValues
+}
+
+// valueSerializer returns the expression serializing values of managed types (with a `TSSerialize` expression) nested
+// in slices and maps, or value if there are none. Classes are serialized by their `toJSON()`.
+func (t *TypeScriptify) valueSerializer(typ reflect.Type, value string, depth int) string {
+	if opts, found := t.typeOptions(typ); found {
+		if opts.TSSerialize == "" {
+			return value
+		}
+		return strings.Replace(opts.TSSerialize, "__VALUE__", value, -1)
+	}
+
+	v := fmt.Sprintf("v%d", depth)
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.valueSerializer(typ.Elem(), value, depth)
+	case reflect.Slice, reflect.Array:
+		elem := fmt.Sprintf("e%d", depth)
+		serializer := t.valueSerializer(typ.Elem(), elem, depth+1)
+		if serializer == elem {
+			return value
+		}
+		return fmt.Sprintf("((%s: any) => %s == null ? %s : (%s as any[]).map((%s: any) => %s))(%s)", v, v, v, v, elem, serializer, value)
+	case reflect.Map:
+		key := fmt.Sprintf("k%d", depth)
+		serializer := t.valueSerializer(typ.Elem(), fmt.Sprintf("%s[%s]", v, key), depth+1)
+		if serializer == fmt.Sprintf("%s[%s]", v, key) {
+			return value
+		}
+		return fmt.Sprintf("((%s: any) => { if (%s == null) return %s; const r%d: any = {}; for (const %s of Object.keys(%s)) r%d[%s] = %s; return r%d; })(%s)", v, v, v, depth, key, v, depth, key, serializer, depth, value)
+	}
+	return value
+}
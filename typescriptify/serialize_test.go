@@ -0,0 +1,84 @@
+package typescriptify
+
+import (
+	"testing"
+	"time"
+)
+
+type Event struct {
+	Name  string     `json:"name"`
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end"`
+}
+
+type Calendar struct {
+	Title  string            `json:"title"`
+	Main   Event             `json:"main"`
+	Events []Event           `json:"events"`
+	ByName map[string]*Event `json:"by_name"`
+}
+
+func TestSerialize(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Calendar{}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)", TSSerialize: "__VALUE__.toISOString()"}).
+		WithBackupDir("")
+
+	desiredResult := `export class Event {
+    name: string;
+    start: Date;
+    end?: Date;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.start = new Date(source["start"]);
+        this.end = new Date(source["end"]);
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "start": this.start.toISOString(),
+            "end": this.end == null ? this.end : this.end.toISOString(),
+        };
+    }
+}
+export class Calendar {
+    title: string;
+    main: Event;
+    events: Event[];
+    by_name: {[key: string]: Event};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.title = source["title"];
+        this.main = this.convertValues(source["main"], Event);
+        this.events = this.convertValues(source["events"], Event);
+        this.by_name = this.convertValues(source["by_name"], Event, true);
+    }
+
+    toJSON(): any {
+        return {
+            ...this,
+            "main": this.serializeValues(this.main),
+            "events": this.serializeValues(this.events),
+            "by_name": this.serializeValues(this.by_name),
+        };
+    }
+
+	` + tsConvertValuesFunc + `
+
+	` + tsSerializeValuesFunc + `
+}`
+	jsn := `{"title": "c", "main": {"name": "a", "start": "2021-01-02T03:04:05.000Z", "end": "2021-01-03T03:04:05.000Z"}, "events": [{"name": "b", "start": "2022-01-02T03:04:05.000Z", "end": "2022-01-03T03:04:05.000Z"}], "by_name": {"x": {"name": "x", "start": "2023-01-02T03:04:05.000Z", "end": "2023-01-03T03:04:05.000Z"}}}`
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Calendar(` + jsn + `).main.start instanceof Date`,
+		`new Calendar(` + jsn + `).toJSON().main.start === "2021-01-02T03:04:05.000Z"`,
+		`new Calendar(` + jsn + `).toJSON().events[0].end === "2022-01-03T03:04:05.000Z"`,
+		`new Calendar(` + jsn + `).toJSON().by_name["x"].start === "2023-01-02T03:04:05.000Z"`,
+		`JSON.parse(JSON.stringify(new Calendar(` + jsn + `))).events[0].start === "2022-01-02T03:04:05.000Z"`,
+		`JSON.stringify(new Calendar(` + jsn + `)) === JSON.stringify(` + jsn + `)`,
+	})
+}
@@ -0,0 +1,16 @@
+// Package billing is used to test the conversion of types from multiple packages.
+package billing
+
+import "github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users"
+
+type Invoice struct {
+	Number   string         `json:"number"`
+	Customer users.User     `json:"customer"`
+	Lines    []InvoiceLine  `json:"lines"`
+	Shipping *users.Address `json:"shipping"`
+}
+
+type InvoiceLine struct {
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
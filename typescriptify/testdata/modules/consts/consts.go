@@ -0,0 +1,6 @@
+// Package consts is used to test the conversion of a package named like the module of untyped constants.
+package consts
+
+type Limits struct {
+	MaxPageSize int `json:"max_page_size"`
+}
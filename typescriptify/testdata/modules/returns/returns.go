@@ -0,0 +1,12 @@
+// Package returns is used to test the conversion of types with the same name used in one module.
+package returns
+
+import (
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/shipping"
+	"github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users"
+)
+
+type Return struct {
+	Pickup   shipping.Address `json:"pickup"`
+	Customer users.Address    `json:"customer"`
+}
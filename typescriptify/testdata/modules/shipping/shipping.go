@@ -0,0 +1,16 @@
+// Package shipping is used to test the conversion of types from multiple packages.
+package shipping
+
+import "github.com/tkrajina/typescriptify-golang-structs/typescriptify/testdata/modules/users"
+
+// Address has the same name as users.Address.
+type Address struct {
+	Line    string `json:"line"`
+	Country string `json:"country"`
+}
+
+type Shipment struct {
+	Recipient users.User `json:"recipient"`
+	From      Address    `json:"from"`
+	To        []*Address `json:"to"`
+}
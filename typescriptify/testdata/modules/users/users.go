@@ -0,0 +1,12 @@
+// Package users is used to test the conversion of types from multiple packages.
+package users
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type User struct {
+	Name    string  `json:"name"`
+	Address Address `json:"address"`
+}
@@ -1,6 +1,7 @@
 package typescriptify
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -16,20 +17,23 @@ const (
 	tsDocTag            = "ts_doc"
 	tsTransformTag      = "ts_transform"
 	tsType              = "ts_type"
-	tsConvertValuesFunc = `convertValues(a: any, classs: any, asMap: boolean = false): any {
-	if (!a) {
+	tsConvertValuesFunc = `convertValues(a: any, classs: any, asMap: boolean = false, ...typeArgs: any[]): any {
+	if (!a || !classs) {
 		return a;
 	}
 	if (a.slice) {
-		return (a as any[]).map(elem => this.convertValues(elem, classs));
+		return (a as any[]).map(elem => this.convertValues(elem, classs, false, ...typeArgs));
 	} else if ("object" === typeof a) {
 		if (asMap) {
 			for (const key of Object.keys(a)) {
-				a[key] = new classs(a[key]);
+				a[key] = this.convertValues(a[key], classs, false, ...typeArgs);
 			}
 			return a;
 		}
-		return new classs(a);
+		if (classs.variants) {
+			return this.convertValues(a, classs.variants[a[classs.discriminator]], false, ...typeArgs);
+		}
+		return new classs(a, ...typeArgs);
 	}
 	return a;
 }`
@@ -40,6 +44,8 @@ type TypeOptions struct {
 	TSType      string
 	TSDoc       string
 	TSTransform string
+	TSSerialize string
+	TSZod       string
 }
 
 // StructType stores settings for transforming one Golang struct.
@@ -86,17 +92,49 @@ type TypeScriptify struct {
 	BackupDir         string // If empty no backup
 	DontExport        bool
 	CreateInterface   bool
+	EmbeddedAsExtends bool // Extend embedded structs instead of copying their fields
+	CreateZodSchema   bool // Create zod schemas instead of classes/interfaces
+	CreateTypeGuards  bool // Create `isX(x: unknown): x is X` functions
+	CreateDirIndex    bool // Create an index.ts module in ConvertToDir()
+	NameCollisions    NameCollisionStrategy
+	CollisionNamer    func(typ reflect.Type) string // Used with CustomNameCollision
+	InlineAnonymous   bool                          // Object literal types for anonymous structs (instead of `Parent_Field` classes/interfaces)
+	InferMarshalJSON  bool                          // Infer the type of json.Marshaler types from their zero value (otherwise they need a ts_type)
+	Nullability       NullabilityMode               // How pointers, slices and maps (null if nil) are converted
+	StringMapKeys     bool                          // Integer map keys are `string` (like in JSON) instead of `number`
+	RecordMaps        bool                          // Maps are `Record<K, V>` instead of index signatures
+	BytesAsUint8Array bool                          // Byte slices (base64 strings) are decoded to `Uint8Array` in classes
+	RawMessageType    string                        // TypeScript type of `json.RawMessage` values
+	Int64Mode         Int64Mode                     // How int64 and uint64 values (possibly above 2^53) are converted
+	Aliases           AliasMode                     // Named primitive types as (branded) type aliases
+	EnumStyle         EnumStyle                     // Enums as `enum`, `const enum`, unions or `as const` objects
+	ConstsModule      string                        // Module of constants without a converted type in ConvertFiles()
 	customImports     []string
 
 	structTypes []StructType
 	enumTypes   []EnumType
 	enums       map[reflect.Type][]enumElement
+	flagEnums   map[reflect.Type]bool
+	consts      []constElement
+	unionTypes  []reflect.Type
+	unions      map[reflect.Type]UnionType
 	kinds       map[reflect.Kind]string
 
 	fieldTypeOptions map[reflect.Type]TypeOptions
 
 	// throwaway, used when converting
-	alreadyConverted map[reflect.Type]bool
+	alreadyConverted         map[reflect.Type]bool
+	alreadyConvertedGenerics map[*genericStruct]bool
+	generics                 map[string]*genericStruct
+	zodDeclared              map[string]bool
+	zodLazy                  map[string]bool
+	declarations             []declaration
+	references               []map[string]bool
+	names                    map[string]string
+	moduleScopes             map[string]map[string]bool // Package path => name keys of the types used in its module
+	anonymousParents         map[reflect.Type]anonymousParent
+	marshalers               map[reflect.Type]string
+	warnings                 []string
 }
 
 func New() *TypeScriptify {
@@ -129,37 +167,53 @@ func New() *TypeScriptify {
 	result.Indent = "    "
 	result.CreateFromMethod = false
 	result.CreateConstructor = true
+	result.InferMarshalJSON = true
+	result.RawMessageType = "unknown"
+	result.ConstsModule = "consts.ts"
 
 	return result
 }
 
-func deepFields(typeOf reflect.Type) []reflect.StructField {
-	fields := make([]reflect.StructField, 0)
-
-	if typeOf.Kind() == reflect.Ptr {
-		typeOf = typeOf.Elem()
-	}
-
-	if typeOf.Kind() != reflect.Struct {
-		return fields
+// embeddedBases returns the embedded structs which can be extended instead of flattened, and the indexes (in
+// `deepFields()`) of the fields inherited from them.
+//
+// If any field of an embedded struct is shadowed (or annihilated by a conflicting field), nothing is extended. Embedded
+// pointers are always flattened, because the inherited fields are missing in the JSON when the pointer is nil.
+func (t *TypeScriptify) embeddedBases(typeOf reflect.Type) ([]reflect.Type, map[int]bool) {
+	if !t.EmbeddedAsExtends || t.genericOf(typeOf) != nil {
+		return nil, nil
 	}
 
+	fields := deepFields(typeOf)
+	var bases []reflect.Type
+	inherited := map[int]bool{}
 	for i := 0; i < typeOf.NumField(); i++ {
 		f := typeOf.Field(i)
-
-		kind := f.Type.Kind()
-		if f.Anonymous && kind == reflect.Struct {
-			//fmt.Println(v.Interface())
-			fields = append(fields, deepFields(f.Type)...)
-		} else if f.Anonymous && kind == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct {
-			//fmt.Println(v.Interface())
-			fields = append(fields, deepFields(f.Type.Elem())...)
-		} else {
-			fields = append(fields, f)
+		baseType := f.Type
+		tag := f.Tag.Get("json")
+		if !f.Anonymous || baseType.Kind() != reflect.Struct || tag == "-" || parseJSONTag(tag).validName() != "" {
+			continue
+		}
+		if !t.CreateInterface && len(bases) > 0 {
+			// Classes can extend only one class
+			continue
+		}
+		var indexes []int
+		for n, fld := range fields {
+			if len(fld.Index) > 1 && fld.Index[0] == i {
+				indexes = append(indexes, n)
+			}
 		}
+		if len(indexes) != len(deepFields(baseType)) {
+			return nil, nil
+		}
+		for _, n := range indexes {
+			inherited[n] = true
+		}
+		bases = append(bases, baseType)
 	}
 
-	return fields
+	return bases, inherited
 }
 
 func (ts TypeScriptify) logf(depth int, s string, args ...interface{}) {
@@ -194,6 +248,13 @@ func (t *TypeScriptify) WithInterface(b bool) *TypeScriptify {
 	return t
 }
 
+// WithEmbeddedAsExtends converts embedded structs on their own, and the embedding struct extends them (instead of
+// containing a copy of their fields).
+func (t *TypeScriptify) WithEmbeddedAsExtends(b bool) *TypeScriptify {
+	t.EmbeddedAsExtends = b
+	return t
+}
+
 func (t *TypeScriptify) WithConstructor(b bool) *TypeScriptify {
 	t.CreateConstructor = b
 	return t
@@ -238,36 +299,6 @@ func (t *TypeScriptify) AddType(typeOf reflect.Type) *TypeScriptify {
 	return t
 }
 
-func (t *typeScriptClassBuilder) AddMapField(fieldName string, field reflect.StructField) {
-	keyType := field.Type.Key()
-	valueType := field.Type.Elem()
-	valueTypeName := valueType.Name()
-	if name, ok := t.types[valueType.Kind()]; ok {
-		valueTypeName = name
-	}
-	if valueType.Kind() == reflect.Array || valueType.Kind() == reflect.Slice {
-		valueTypeName = valueType.Elem().Name() + "[]"
-	}
-	if valueType.Kind() == reflect.Ptr {
-		valueTypeName = valueType.Elem().Name()
-	}
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-
-	keyTypeStr := keyType.Name()
-	// Key should always be string, no need for this:
-	// _, isSimple := t.types[keyType.Kind()]
-	// if !isSimple {
-	// 	keyTypeStr = t.prefix + keyType.Name() + t.suffix
-	// }
-
-	t.fields = append(t.fields, fmt.Sprintf("%s%s: {[key: %s]: %s};", t.indent, fieldName, keyTypeStr, valueTypeName))
-	if valueType.Kind() == reflect.Struct {
-		t.constructorBody = append(t.constructorBody, fmt.Sprintf("%s%sthis.%s = this.convertValues(source[\"%s\"], %s, true);", t.indent, t.indent, strippedFieldName, strippedFieldName, t.prefix+valueTypeName+t.suffix))
-	} else {
-		t.constructorBody = append(t.constructorBody, fmt.Sprintf("%s%sthis.%s = source[\"%s\"];", t.indent, t.indent, strippedFieldName, strippedFieldName))
-	}
-}
-
 func (t *TypeScriptify) AddEnum(values interface{}) *TypeScriptify {
 	if t.enums == nil {
 		t.enums = map[reflect.Type][]enumElement{}
@@ -319,14 +350,41 @@ func (t *TypeScriptify) AddEnumValues(typeOf reflect.Type, values interface{}) *
 }
 
 func (t *TypeScriptify) Convert(customCode map[string]string) (string, error) {
+	return t.convert(customCode, false)
+}
+
+// convert converts all registered types, with modules the names of types are resolved for `ConvertFiles()`.
+func (t *TypeScriptify) convert(customCode map[string]string, modules bool) (string, error) {
 	if t.CreateFromMethod {
 		fmt.Fprintln(os.Stderr, "FromMethod METHOD IS DEPRECATED AND WILL BE REMOVED!!!!!!")
 	}
+	if t.Int64Mode == Int64AsBigInt && t.CreateInterface && !t.CreateZodSchema {
+		return "", errors.New("Int64AsBigInt can't be used with interfaces, nothing parses their JSON with BigInt() (use Int64AsString)")
+	}
 
 	t.alreadyConverted = make(map[reflect.Type]bool)
+	t.alreadyConvertedGenerics = make(map[*genericStruct]bool)
+	t.zodDeclared = make(map[string]bool)
+	t.zodLazy = make(map[string]bool)
+	t.declarations = nil
+	t.references = nil
+	t.warnings = nil
+	if err := t.collectEnumProviders(); err != nil {
+		return "", err
+	}
+	t.collectGenerics()
+	if err := t.collectMarshalers(); err != nil {
+		return "", err
+	}
+	if err := t.resolveNames(modules); err != nil {
+		return "", err
+	}
 	depth := 0
 
 	result := ""
+	if t.CreateZodSchema {
+		result += "import { z } from \"zod\";\n"
+	}
 	if len(t.customImports) > 0 {
 		// Put the custom imports, i.e.: `import Decimal from 'decimal.js'`
 		for _, cimport := range t.customImports {
@@ -343,6 +401,14 @@ func (t *TypeScriptify) Convert(customCode map[string]string) (string, error) {
 		result += "\n" + strings.Trim(typeScriptCode, " "+t.Indent+"\r\n")
 	}
 
+	for _, unionTyp := range t.unionTypes {
+		typeScriptCode, err := t.convertUnion(depth, t.unions[unionTyp], customCode)
+		if err != nil {
+			return "", err
+		}
+		result += "\n" + strings.Trim(typeScriptCode, " "+t.Indent+"\r\n")
+	}
+
 	for _, strctTyp := range t.structTypes {
 		typeScriptCode, err := t.convertType(depth, strctTyp.Type, customCode)
 		if err != nil {
@@ -350,6 +416,14 @@ func (t *TypeScriptify) Convert(customCode map[string]string) (string, error) {
 		}
 		result += "\n" + strings.Trim(typeScriptCode, " "+t.Indent+"\r\n")
 	}
+
+	for _, c := range t.consts {
+		typeScriptCode, err := t.convertConst(depth, c)
+		if err != nil {
+			return "", err
+		}
+		result += "\n" + strings.Trim(typeScriptCode, " "+t.Indent+"\r\n")
+	}
 	return result, nil
 }
 
@@ -413,7 +487,7 @@ func (t TypeScriptify) backup(fileName string) error {
 	return ioutil.WriteFile(backupFn, bytes, os.FileMode(0700))
 }
 
-func (t TypeScriptify) ConvertToFile(fileName string) error {
+func (t *TypeScriptify) ConvertToFile(fileName string) error {
 	if len(t.BackupDir) > 0 {
 		err := t.backup(fileName)
 		if err != nil {
@@ -460,21 +534,52 @@ func (t *TypeScriptify) convertEnum(depth int, typeOf reflect.Type, elements []e
 		return "", nil
 	}
 	t.alreadyConverted[typeOf] = true
+	t.beginDeclaration()
 
-	entityName := t.Prefix + typeOf.Name() + t.Suffix
-	result := "enum " + entityName + " {\n"
+	entityName := t.typeName(typeOf)
+	var literals, refs []string
+	for _, el := range elements {
+		literal, err := t.enumValueLiteral(entityName, el)
+		if err != nil {
+			return "", err
+		}
+		literals = append(literals, literal)
+		refs = append(refs, t.enumMemberRef(entityName, el, literal))
+	}
 
-	for _, val := range elements {
-		result += fmt.Sprintf("%s%s = %#v,\n", t.Indent, val.name, val.value)
+	export := ""
+	if !t.DontExport {
+		export = "export "
 	}
+	result := t.enumDeclaration(export, entityName, elements, literals)
 
-	result += "}"
+	var mask uint64
+	if t.flagEnums[typeOf] {
+		if t.EnumStyle != EnumDeclaration && t.EnumStyle != ConstEnumDeclaration {
+			return "", fmt.Errorf("flags enum %s: values combining flags can't be typed with an union, use the EnumDeclaration or ConstEnumDeclaration style", entityName)
+		}
+		var err error
+		if mask, err = t.flagsMask(entityName, elements); err != nil {
+			return "", err
+		}
+		result += "\n" + t.flagsHelpers(export, entityName, elements, refs)
+	}
 
-	if !t.DontExport {
-		result = "export " + result
+	if t.CreateZodSchema {
+		schemaName := zodSchemaName(entityName)
+		t.zodDeclared[schemaName] = true
+		schema := t.enumZodSchema(entityName, literals)
+		if t.flagEnums[typeOf] {
+			schema = flagsZodSchema(mask)
+		}
+		result += fmt.Sprintf("\n%sconst %s = %s;", export, schemaName, schema)
+	} else if t.CreateTypeGuards && t.flagEnums[typeOf] {
+		result += "\n" + t.flagsGuard(export, entityName, mask)
+	} else if t.CreateTypeGuards {
+		result += "\n" + t.enumGuard(entityName, refs)
 	}
 
-	return result, nil
+	return t.declare(typeOf, result), nil
 }
 
 func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.StructField) TypeOptions {
@@ -483,6 +588,8 @@ func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.S
 		TSTransform: field.Tag.Get(tsTransformTag),
 		TSType:      field.Tag.Get(tsType),
 		TSDoc:       field.Tag.Get(tsDocTag),
+		TSSerialize: field.Tag.Get(tsSerializeTag),
+		TSZod:       field.Tag.Get(tsZodTag),
 	}
 
 	overrides := []TypeOptions{}
@@ -510,40 +617,67 @@ func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.S
 		if o.TSType != "" {
 			opts.TSType = o.TSType
 		}
+		if o.TSSerialize != "" {
+			opts.TSSerialize = o.TSSerialize
+		}
+		if o.TSZod != "" {
+			opts.TSZod = o.TSZod
+		}
+	}
+
+	if opts.TSType == "" {
+		// Values with a json/v2 format, byte slices and raw JSON, types with a custom JSON encoding, and values encoded as
+		// strings (`json:",string"`):
+		tag := parseJSONTag(field.Tag.Get("json"))
+		if tsType, zodSchema, _, is := formatType(field.Type, tag.value("format")); is {
+			opts.TSType = tsType
+			if opts.TSZod == "" {
+				opts.TSZod = zodSchema
+			}
+		} else if builtin, is := t.builtinTypeOptions(field.Type); is {
+			opts.TSType = builtin.TSType
+			if opts.TSTransform == "" {
+				opts.TSTransform = builtin.TSTransform
+			}
+			if opts.TSSerialize == "" {
+				opts.TSSerialize = builtin.TSSerialize
+			}
+			if opts.TSZod == "" {
+				opts.TSZod = builtin.TSZod
+			}
+		} else if tsType, zodSchema, is := t.marshalerType(field.Type); is {
+			opts.TSType = tsType
+			if opts.TSZod == "" {
+				opts.TSZod = zodSchema
+			}
+		} else if t.quotedField(field) {
+			opts.TSType = "string"
+			if opts.TSZod == "" {
+				opts.TSZod = "z.string()"
+			}
+		}
 	}
 
 	return opts
 }
 
+// getJSONFieldName returns the JSON name of a field (from `deepFields()`), with a `?` suffix if the field is optional.
 func (t *TypeScriptify) getJSONFieldName(field reflect.StructField, isPtr bool) string {
-	jsonFieldName := ""
+	if !field.IsExported() && !field.Anonymous || isInlineMap(field) {
+		return ""
+	}
 	jsonTag := field.Tag.Get("json")
-	if len(jsonTag) > 0 {
-		jsonTagParts := strings.Split(jsonTag, ",")
-		if len(jsonTagParts) > 0 {
-			jsonFieldName = strings.Trim(jsonTagParts[0], t.Indent)
-		}
-		hasOmitEmpty := false
-		ignored := false
-		for _, t := range jsonTagParts {
-			if t == "" {
-				break
-			}
-			if t == "omitempty" {
-				hasOmitEmpty = true
-				break
-			}
-			if t == "-" {
-				ignored = true
-				break
-			}
-		}
-		if !ignored && isPtr || hasOmitEmpty {
-			jsonFieldName = fmt.Sprintf("%s?", jsonFieldName)
-		}
-	} else if /*field.IsExported()*/ field.PkgPath == "" {
+	if jsonTag == "-" {
+		return ""
+	}
+	tag := parseJSONTag(jsonTag)
+	jsonFieldName := tag.validName()
+	if jsonFieldName == "" {
 		jsonFieldName = field.Name
 	}
+	if t.isOptional(field, isPtr) {
+		jsonFieldName += "?"
+	}
 	return jsonFieldName
 }
 
@@ -554,41 +688,143 @@ func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode m
 	t.logf(depth, "Converting type %s", typeOf.String())
 
 	t.alreadyConverted[typeOf] = true
+	t.beginDeclaration()
+
+	entityName := t.structName(typeOf)
+	generic := t.genericOf(typeOf)
+	typeParams, ctorParams, createFromParams, ctorArgs := "", "", "", ""
+	if generic != nil {
+		typeParams = "<" + strings.Join(generic.params, ", ") + ">"
+		for _, param := range generic.params {
+			// Functions converting the values of the type arguments, see `typeArgConverters()`:
+			ctorParams += ", convert" + param + ": (v: any) => any = (v) => v"
+			createFromParams += ", convert" + param + "?: (v: any) => any"
+			ctorArgs += ", convert" + param
+		}
+	}
 
-	entityName := t.Prefix + typeOf.Name() + t.Suffix
+	deps := ""
 	result := ""
+
+	bases, inherited := t.embeddedBases(typeOf)
+	extends := []string{}
+	superArgs := ""
+	for _, base := range bases {
+		t.logf(depth, "- extends %s", base.String())
+		typeScriptChunk, err := t.convertType(depth+1, base, customCode)
+		if err != nil {
+			return "", err
+		}
+		if typeScriptChunk != "" {
+			deps = typeScriptChunk + "\n" + deps
+		}
+		baseName, _, classArgs := t.structRef(base)
+		extends = append(extends, baseName)
+		for _, classArg := range classArgs {
+			superArgs += ", " + classArg
+		}
+	}
+	if len(extends) > 0 {
+		typeParams += " extends " + strings.Join(extends, ", ")
+	}
+
 	if t.CreateInterface {
-		result += fmt.Sprintf("interface %s {\n", entityName)
+		result += fmt.Sprintf("interface %s%s {\n", entityName, typeParams)
 	} else {
-		result += fmt.Sprintf("class %s {\n", entityName)
+		result += fmt.Sprintf("class %s%s {\n", entityName, typeParams)
 	}
 	if !t.DontExport {
 		result = "export " + result
 	}
 	builder := typeScriptClassBuilder{
-		types:  t.kinds,
-		indent: t.Indent,
-		prefix: t.Prefix,
-		suffix: t.Suffix,
+		types:     t.kinds,
+		indent:    t.Indent,
+		typeName:  t.typeRef,
+		structRef: t.structRef,
+		mapType:   t.mapType,
 	}
 
+	var fieldInfos []fieldInfo
+	var inlineMap *reflect.StructField
 	fields := deepFields(typeOf)
-	for _, field := range fields {
+	for fieldIndex, field := range fields {
+		if inherited[fieldIndex] {
+			continue
+		}
+		if isInlineMap(field) {
+			inlineMap = &fields[fieldIndex]
+			continue
+		}
+		fieldTypes := []reflect.Type{field.Type}
+		if generic != nil {
+			fieldTypes = generic.fieldTypes(fieldIndex)
+		}
 		isPtr := field.Type.Kind() == reflect.Ptr
 		if isPtr {
 			field.Type = field.Type.Elem()
 		}
 		jsonFieldName := t.getJSONFieldName(field, isPtr)
-		if len(jsonFieldName) == 0 || jsonFieldName == "-" {
+		if len(jsonFieldName) == 0 {
 			continue
 		}
 
 		var err error
 		fldOpts := t.getFieldOptions(typeOf, field)
+		if err := t.checkInt64Field(typeOf, field, fldOpts); err != nil {
+			return "", err
+		}
+		if fldOpts.TSType == "" && containerElem(field.Type) != field.Type {
+			hasParams := false
+			if generic != nil {
+				_, hasParams = t.genericTypeExpr(generic, fieldTypes)
+			}
+			if !hasParams {
+				// Slices and maps of 64-bit integers are JSON numbers, whatever the Int64Mode:
+				field.Type = t.int64Numbers(field.Type)
+				fieldTypes = mapTypes(fieldTypes, t.int64Numbers)
+			}
+		}
+		if generic != nil && field.Tag.Get(tsType) == "" && field.Tag.Get(tsTransformTag) == "" {
+			if _, hasParams := t.genericTypeExpr(generic, fieldTypes); hasParams {
+				// Options inferred from the type argument (like its custom marshaler) are those of one instantiation:
+				fldOpts = TypeOptions{TSDoc: fldOpts.TSDoc, TSZod: field.Tag.Get(tsZodTag)}
+			}
+		}
 		if fldOpts.TSDoc != "" {
 			result += "\t/** " + fldOpts.TSDoc + " */\n"
 		}
-		if fldOpts.TSTransform != "" {
+		if discriminator, is := t.discriminatorValue(typeOf, strings.ReplaceAll(jsonFieldName, "?", "")); is && fldOpts.TSType == "" {
+			fldOpts.TSType = fmt.Sprintf("%q", discriminator)
+			fldOpts.TSZod = fmt.Sprintf("z.literal(%q)", discriminator)
+		}
+		nullable := t.isNullable(field, isPtr)
+		fieldInfos = append(fieldInfos, fieldInfo{name: jsonFieldName, doc: fldOpts.TSDoc, types: fieldTypes, opts: fldOpts, nullable: nullable, canBeNull: t.canBeNull(field, isPtr), quoted: t.quotedField(field)})
+		builder.nullable = nullable
+		if generic != nil && fldOpts.TSType == "" && fldOpts.TSTransform == "" {
+			if tsType, hasParams := t.genericTypeExpr(generic, fieldTypes); hasParams {
+				t.logf(depth, "- generic field %s.%s", typeOf.Name(), field.Name)
+				typeScriptChunk, err := t.convertDependencies(depth+1, field.Type, customCode)
+				if err != nil {
+					return "", err
+				}
+				deps = typeScriptChunk + deps
+				strippedFieldName := strings.ReplaceAll(jsonFieldName, "?", "")
+				builder.AddFieldWithInitializer(jsonFieldName, tsType, t.valueInitializer(generic, fieldTypes, fmt.Sprintf("source[\"%s\"]", strippedFieldName), 1))
+				continue
+			}
+		}
+		if t.isAliasField(field.Type, fldOpts) {
+			t.logf(depth, "- alias field %s.%s", typeOf.Name(), field.Name)
+			deps = t.convertAlias(depth+1, field.Type) + deps
+			initializer := fmt.Sprintf("source[\"%s\"]", strings.ReplaceAll(jsonFieldName, "?", ""))
+			if fldOpts.TSTransform != "" {
+				initializer = strings.Replace(fldOpts.TSTransform, "__VALUE__", initializer, -1)
+			}
+			builder.AddFieldWithInitializer(jsonFieldName, t.typeRef(field.Type), initializer)
+		} else if union, arrayDepth, asMap, isUnion := t.unionField(field.Type); isUnion && fldOpts.TSType == "" {
+			t.logf(depth, "- union field %s.%s", typeOf.Name(), field.Name)
+			builder.AddUnionField(jsonFieldName, field, t.typeRef(union.Type), arrayDepth, asMap)
+		} else if fldOpts.TSTransform != "" {
 			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
 			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
 		} else if _, isEnum := t.enums[field.Type]; isEnum {
@@ -597,6 +833,19 @@ func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode m
 		} else if fldOpts.TSType != "" { // Struct:
 			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
 			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
+		} else if t.hasInlineStruct(field.Type) || field.Type.Kind() == reflect.Map || field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Array {
+			t.logf(depth, "- nested field %s.%s (%s)", typeOf.Name(), field.Name, field.Type.String())
+			if elem := containerElem(field.Type); !t.isManaged(elem) && elem.Kind() != reflect.Struct {
+				if _, found := t.kinds[elem.Kind()]; !found {
+					return "", fmt.Errorf("cannot find type for %s (%s/%s)", elem.Kind().String(), jsonFieldName, field.Type.String())
+				}
+			}
+			typeScriptChunk, err := t.convertDependencies(depth+1, field.Type, customCode)
+			if err != nil {
+				return "", err
+			}
+			deps = typeScriptChunk + deps
+			builder.AddFieldWithInitializer(jsonFieldName, t.typeExpr(field.Type), t.valueInitializer(nil, []reflect.Type{field.Type}, fmt.Sprintf("source[\"%s\"]", strings.ReplaceAll(jsonFieldName, "?", "")), 1))
 		} else if field.Type.Kind() == reflect.Struct { // Struct:
 			t.logf(depth, "- struct %s.%s (%s)", typeOf.Name(), field.Name, field.Type.String())
 			typeScriptChunk, err := t.convertType(depth+1, field.Type, customCode)
@@ -604,72 +853,9 @@ func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode m
 				return "", err
 			}
 			if typeScriptChunk != "" {
-				result = typeScriptChunk + "\n" + result
+				deps = typeScriptChunk + "\n" + deps
 			}
 			builder.AddStructField(jsonFieldName, field)
-		} else if field.Type.Kind() == reflect.Map {
-			t.logf(depth, "- map field %s.%s", typeOf.Name(), field.Name)
-			// Also convert map key types if needed
-			var keyTypeToConvert reflect.Type
-			switch field.Type.Key().Kind() {
-			case reflect.Struct:
-				keyTypeToConvert = field.Type.Key()
-			case reflect.Ptr:
-				keyTypeToConvert = field.Type.Key().Elem()
-			}
-			if keyTypeToConvert != nil {
-				typeScriptChunk, err := t.convertType(depth+1, keyTypeToConvert, customCode)
-				if err != nil {
-					return "", err
-				}
-				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
-				}
-			}
-			// Also convert map value types if needed
-			var valueTypeToConvert reflect.Type
-			switch field.Type.Elem().Kind() {
-			case reflect.Struct:
-				valueTypeToConvert = field.Type.Elem()
-			case reflect.Ptr:
-				valueTypeToConvert = field.Type.Elem().Elem()
-			}
-			if valueTypeToConvert != nil {
-				typeScriptChunk, err := t.convertType(depth+1, valueTypeToConvert, customCode)
-				if err != nil {
-					return "", err
-				}
-				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
-				}
-			}
-
-			builder.AddMapField(jsonFieldName, field)
-		} else if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Array { // Slice:
-			if field.Type.Elem().Kind() == reflect.Ptr { //extract ptr type
-				field.Type = field.Type.Elem()
-			}
-
-			arrayDepth := 1
-			for field.Type.Elem().Kind() == reflect.Slice { // Slice of slices:
-				field.Type = field.Type.Elem()
-				arrayDepth++
-			}
-
-			if field.Type.Elem().Kind() == reflect.Struct { // Slice of structs:
-				t.logf(depth, "- struct slice %s.%s (%s)", typeOf.Name(), field.Name, field.Type.String())
-				typeScriptChunk, err := t.convertType(depth+1, field.Type.Elem(), customCode)
-				if err != nil {
-					return "", err
-				}
-				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
-				}
-				builder.AddArrayOfStructsField(jsonFieldName, field, arrayDepth)
-			} else { // Slice of simple fields:
-				t.logf(depth, "- slice field %s.%s", typeOf.Name(), field.Name)
-				err = builder.AddSimpleArrayField(jsonFieldName, field, arrayDepth, fldOpts)
-			}
 		} else { // Simple field:
 			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
 			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
@@ -679,28 +865,74 @@ func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode m
 		}
 	}
 
+	catchall := ""
+	if inlineMap != nil {
+		t.logf(depth, "- inline map %s.%s", typeOf.Name(), inlineMap.Name)
+		valueType := inlineMap.Type
+		if valueType.Kind() == reflect.Ptr {
+			valueType = valueType.Elem()
+		}
+		valueType = valueType.Elem()
+		typeScriptChunk, err := t.convertDependencies(depth+1, valueType, customCode)
+		if err != nil {
+			return "", err
+		}
+		deps = typeScriptChunk + deps
+		var names []string
+		for _, field := range fields {
+			if name := t.getJSONFieldName(field, false); name != "" {
+				names = append(names, strings.ReplaceAll(name, "?", ""))
+			}
+		}
+		builder.AddIndexSignature(t.indexSignatureType(generic, valueType, fieldInfos), t.valueInitializer(nil, []reflect.Type{valueType}, "source[key]", 1), names)
+		catchall = t.zodSchema(nil, []reflect.Type{valueType})
+	}
+
 	if t.CreateFromMethod {
 		t.CreateConstructor = true
 	}
 
+	if generic != nil {
+		if t.alreadyConvertedGenerics[generic] {
+			// Another instantiation of the same generic struct, only its dependencies are needed:
+			t.endDeclaration()
+			return strings.TrimRight(deps, "\n"), nil
+		}
+		t.alreadyConvertedGenerics[generic] = true
+	}
+
+	if t.CreateZodSchema {
+		return deps + t.declare(typeOf, t.zodDeclaration(entityName, entityName+typeParams, generic, bases, builder, fieldInfos, catchall)), nil
+	}
+
 	result += strings.Join(builder.fields, "\n") + "\n"
 	if !t.CreateInterface {
 		constructorBody := strings.Join(builder.constructorBody, "\n")
 		needsConvertValue := strings.Contains(constructorBody, "this.convertValues")
 		if t.CreateFromMethod {
-			result += fmt.Sprintf("\n%sstatic createFrom(source: any = {}) {\n", t.Indent)
-			result += fmt.Sprintf("%s%sreturn new %s(source);\n", t.Indent, t.Indent, entityName)
+			result += fmt.Sprintf("\n%sstatic createFrom(source: any = {}%s) {\n", t.Indent, createFromParams)
+			result += fmt.Sprintf("%s%sreturn new %s(source%s);\n", t.Indent, t.Indent, entityName, ctorArgs)
 			result += fmt.Sprintf("%s}\n", t.Indent)
 		}
 		if t.CreateConstructor {
-			result += fmt.Sprintf("\n%sconstructor(source: any = {}) {\n", t.Indent)
+			result += fmt.Sprintf("\n%sconstructor(source: any = {}%s) {\n", t.Indent, ctorParams)
+			if len(bases) > 0 {
+				result += fmt.Sprintf("%s%ssuper(source%s);\n", t.Indent, t.Indent, superArgs)
+			}
 			result += t.Indent + t.Indent + "if ('string' === typeof source) source = JSON.parse(source);\n"
 			result += constructorBody + "\n"
 			result += fmt.Sprintf("%s}\n", t.Indent)
 		}
+		toJSON, needsSerializeValues := t.toJSONMethod(bases, fieldInfos)
+		if toJSON != "" {
+			result += "\n" + toJSON
+		}
 		if needsConvertValue && (t.CreateConstructor || t.CreateFromMethod) {
 			result += "\n" + indentLines(strings.ReplaceAll(tsConvertValuesFunc, "\t", t.Indent), 1) + "\n"
 		}
+		if needsSerializeValues {
+			result += "\n" + indentLines(strings.ReplaceAll(tsSerializeValuesFunc, "\t", t.Indent), 1) + "\n"
+		}
 	}
 
 	if customCode != nil {
@@ -712,7 +944,56 @@ func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode m
 
 	result += "}"
 
-	return result, nil
+	if t.CreateTypeGuards {
+		result += "\n" + t.structGuard(typeOf, entityName, generic, bases, fieldInfos)
+	}
+
+	return deps + t.declare(typeOf, result), nil
+}
+
+// convertDependencies converts all structs (and type aliases) used in a (pointer, slice, array or map) type.
+func (t *TypeScriptify) convertDependencies(depth int, typ reflect.Type, customCode map[string]string) (string, error) {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return t.convertDependencies(depth, typ.Elem(), customCode)
+	case reflect.Map:
+		keyChunk, err := t.convertDependencies(depth, typ.Key(), customCode)
+		if err != nil {
+			return "", err
+		}
+		valueChunk, err := t.convertDependencies(depth, typ.Elem(), customCode)
+		if err != nil {
+			return "", err
+		}
+		return valueChunk + keyChunk, nil
+	case reflect.Struct:
+		if t.isManaged(typ) {
+			return "", nil
+		}
+		if t.isInlineStruct(typ) {
+			chunks := ""
+			for _, fld := range t.structFields(typ) {
+				if fld.opts.TSType != "" && !t.isAliasField(fld.types[0], fld.opts) {
+					continue
+				}
+				typeScriptChunk, err := t.convertDependencies(depth, fld.types[0], customCode)
+				if err != nil {
+					return "", err
+				}
+				chunks = typeScriptChunk + chunks
+			}
+			return chunks, nil
+		}
+		typeScriptChunk, err := t.convertType(depth, typ, customCode)
+		if err != nil || typeScriptChunk == "" {
+			return "", err
+		}
+		return typeScriptChunk + "\n", nil
+	}
+	if t.isPrimitiveAlias(typ) {
+		return t.convertAlias(depth, typ), nil
+	}
+	return "", nil
 }
 
 func (t *TypeScriptify) AddImport(i string) {
@@ -725,33 +1006,28 @@ func (t *TypeScriptify) AddImport(i string) {
 	t.customImports = append(t.customImports, i)
 }
 
+// fieldInfo stores a converted field for schemas and type guards, they are created only after all the field types
+// are converted.
+type fieldInfo struct {
+	name      string
+	doc       string
+	types     []reflect.Type
+	opts      TypeOptions
+	nullable  bool
+	canBeNull bool // Encoded as `null` if nil, whatever the nullability mode (see `canBeNull()`)
+	quoted    bool // Encoded as a JSON string (`json:",string"`)
+}
+
 type typeScriptClassBuilder struct {
 	types                map[reflect.Kind]string
 	indent               string
 	fields               []string
 	createFromMethodBody []string
 	constructorBody      []string
-	prefix, suffix       string
-}
-
-func (t *typeScriptClassBuilder) AddSimpleArrayField(fieldName string, field reflect.StructField, arrayDepth int, opts TypeOptions) error {
-	fieldType, kind := field.Type.Elem().Name(), field.Type.Elem().Kind()
-	typeScriptType := t.types[kind]
-
-	if len(fieldName) > 0 {
-		strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-		if len(opts.TSType) > 0 {
-			t.addField(fieldName, opts.TSType)
-			t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
-			return nil
-		} else if len(typeScriptType) > 0 {
-			t.addField(fieldName, fmt.Sprint(typeScriptType, strings.Repeat("[]", arrayDepth)))
-			t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
-			return nil
-		}
-	}
-
-	return fmt.Errorf("cannot find type for %s (%s/%s)", kind.String(), fieldName, fieldType)
+	typeName             func(reflect.Type) string
+	structRef            func(reflect.Type) (tsType string, class string, classArgs []string)
+	mapType              func(key reflect.Type, valueType string) string
+	nullable             bool // The next added field is `T | null`
 }
 
 func (t *typeScriptClassBuilder) AddSimpleField(fieldName string, field reflect.StructField, opts TypeOptions) error {
@@ -779,31 +1055,68 @@ func (t *typeScriptClassBuilder) AddSimpleField(fieldName string, field reflect.
 }
 
 func (t *typeScriptClassBuilder) AddEnumField(fieldName string, field reflect.StructField) {
-	fieldType := field.Type.Name()
-	t.addField(fieldName, t.prefix+fieldType+t.suffix)
+	t.addField(fieldName, t.typeName(field.Type))
 	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
 	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
 }
 
 func (t *typeScriptClassBuilder) AddStructField(fieldName string, field reflect.StructField) {
-	fieldType := field.Type.Name()
+	fieldType, class, classArgs := t.structRef(field.Type)
 	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-	t.addField(fieldName, t.prefix+fieldType+t.suffix)
-	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", strippedFieldName, t.prefix+fieldType+t.suffix))
+	t.addField(fieldName, fieldType)
+	t.addInitializerFieldLine(strippedFieldName, convertValuesCall(fmt.Sprintf("source[\"%s\"]", strippedFieldName), class, false, classArgs))
 }
 
-func (t *typeScriptClassBuilder) AddArrayOfStructsField(fieldName string, field reflect.StructField, arrayDepth int) {
-	fieldType := field.Type.Elem().Name()
+// AddUnionField adds a field with an union type, or a slice/map of unions.
+func (t *typeScriptClassBuilder) AddUnionField(fieldName string, field reflect.StructField, unionName string, arrayDepth int, asMap bool) {
 	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-	t.addField(fieldName, fmt.Sprint(t.prefix+fieldType+t.suffix, strings.Repeat("[]", arrayDepth)))
-	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", strippedFieldName, t.prefix+fieldType+t.suffix))
+	fieldType := unionName + strings.Repeat("[]", arrayDepth)
+	if asMap {
+		fieldType = t.mapType(field.Type.Key(), unionName)
+	}
+	t.addField(fieldName, fieldType)
+	t.addInitializerFieldLine(strippedFieldName, convertValuesCall(fmt.Sprintf("source[\"%s\"]", strippedFieldName), unionName, asMap, nil))
+}
+
+// AddFieldWithInitializer adds a field with an already converted type and constructor expression.
+func (t *typeScriptClassBuilder) AddFieldWithInitializer(fieldName, fieldType, initializer string) {
+	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
+	t.addField(fieldName, fieldType)
+	t.addInitializerFieldLine(strippedFieldName, initializer)
+}
+
+// AddIndexSignature adds the index signature for the entries of an inline map, the constructor copies all the
+// properties not declared in the struct.
+func (t *typeScriptClassBuilder) AddIndexSignature(valueType, initializer string, declared []string) {
+	t.fields = append(t.fields, fmt.Sprint(t.indent, "[key: string]: ", valueType, ";"))
+	quoted := make([]string, len(declared))
+	for n, name := range declared {
+		quoted[n] = fmt.Sprintf("%q", name)
+	}
+	for _, obj := range []string{"result", "this"} {
+		body := fmt.Sprintf("%s%sfor (const key of Object.keys(source)) {\n", t.indent, t.indent)
+		body += fmt.Sprintf("%s%s%sif (![%s].includes(key)) %s[key] = %s;\n", t.indent, t.indent, t.indent, strings.Join(quoted, ", "), obj, initializer)
+		body += fmt.Sprintf("%s%s}", t.indent, t.indent)
+		if obj == "result" {
+			t.createFromMethodBody = append(t.createFromMethodBody, body)
+		} else {
+			t.constructorBody = append(t.constructorBody, body)
+		}
+	}
 }
 
 func (t *typeScriptClassBuilder) addInitializerFieldLine(fld, initializer string) {
-	t.createFromMethodBody = append(t.createFromMethodBody, fmt.Sprint(t.indent, t.indent, "result.", fld, " = ", initializer, ";"))
-	t.constructorBody = append(t.constructorBody, fmt.Sprint(t.indent, t.indent, "this.", fld, " = ", initializer, ";"))
+	t.createFromMethodBody = append(t.createFromMethodBody, fmt.Sprint(t.indent, t.indent, tsPropertyAccess("result", fld), " = ", initializer, ";"))
+	t.constructorBody = append(t.constructorBody, fmt.Sprint(t.indent, t.indent, tsPropertyAccess("this", fld), " = ", initializer, ";"))
 }
 
 func (t *typeScriptClassBuilder) addField(fld, fldType string) {
-	t.fields = append(t.fields, fmt.Sprint(t.indent, fld, ": ", fldType, ";"))
+	optional := ""
+	if strings.HasSuffix(fld, "?") {
+		fld, optional = strings.TrimSuffix(fld, "?"), "?"
+	}
+	if t.nullable {
+		fldType += " | null"
+	}
+	t.fields = append(t.fields, fmt.Sprint(t.indent, tsPropertyName(fld), optional, ": ", fldType, ";"))
 }
@@ -18,8 +18,9 @@ type Address struct {
 	// Used in html
 	Duration float64 `json:"duration"`
 	Text1    string  `json:"text,omitempty"`
-	// Ignored:
+	// Named after the Go field:
 	Text2 string `json:",omitempty"`
+	// Ignored:
 	Text3 string `json:"-"`
 }
 
@@ -55,6 +56,7 @@ func TestTypescriptifyWithTypes(t *testing.T) {
 export class Address {
         duration: number;
         text?: string;
+        Text2?: string;
 }
 export class Person {
         name: string;
@@ -86,6 +88,7 @@ export class Dummy {
 export class Address {
         duration: number;
         text?: string;
+        Text2?: string;
 }
 export class Person {
         name: string;
@@ -115,6 +118,7 @@ func TestTypescriptifyWithInstances(t *testing.T) {
 class Address {
         duration: number;
         text?: string;
+        Text2?: string;
 }
 class Person {
         name: string;
@@ -144,6 +148,7 @@ func TestTypescriptifyWithInterfaces(t *testing.T) {
 interface Address {
         duration: number;
         text?: string;
+        Text2?: string;
 }
 interface Person {
         name: string;
@@ -172,6 +177,7 @@ func TestTypescriptifyWithDoubleClasses(t *testing.T) {
 export class Address {
         duration: number;
         text?: string;
+        Text2?: string;
 }
 export class Person {
         name: string;
@@ -207,11 +213,13 @@ func TestWithPrefixes(t *testing.T) {
 class test_Address_test {
     duration: number;
 	text?: string;
+	Text2?: string;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
         this.duration = source["duration"];
         this.text = source["text"];
+        this.Text2 = source["Text2"];
     }
 }
 class test_Person_test {
@@ -257,6 +265,12 @@ class test_Person_test {
 }
 
 func testConverter(t *testing.T, converter *TypeScriptify, strictMode bool, desiredResult string, tsExpressionAndDesiredResults []string) {
+	typeScriptCode := testConvertedCode(t, converter, desiredResult)
+	testTypescriptExpression(t, strictMode, typeScriptCode, tsExpressionAndDesiredResults)
+}
+
+// testConvertedCode only compares the converted code with the expected result (without compiling it).
+func testConvertedCode(t *testing.T, converter *TypeScriptify, desiredResult string) string {
 	typeScriptCode, err := converter.Convert(nil)
 	if err != nil {
 		panic(err.Error())
@@ -299,7 +313,7 @@ func testConverter(t *testing.T, converter *TypeScriptify, strictMode bool, desi
 		t.FailNow()
 	}
 
-	testTypescriptExpression(t, strictMode, typeScriptCode, tsExpressionAndDesiredResults)
+	return typeScriptCode
 }
 
 func testTypescriptExpression(t *testing.T, strictMode bool, baseScript string, tsExpressionAndDesiredResults []string) {
@@ -738,11 +752,13 @@ export class Dummy {
 export class Address {
     duration: number;
     text?: string;
+    Text2?: string;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
         this.duration = source["duration"];
         this.text = source["text"];
+        this.Text2 = source["Text2"];
     }
 }
 export class Person {
@@ -787,11 +803,13 @@ func TestMaps(t *testing.T) {
       export class Address {
           duration: number;
           text?: string;
+          Text2?: string;
       
           constructor(source: any = {}) {
               if ('string' === typeof source) source = JSON.parse(source);
               this.duration = source["duration"];
               this.text = source["text"];
+              this.Text2 = source["Text2"];
 		  }
       }
       export class WithMap {
@@ -0,0 +1,172 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnionType stores settings for transforming one Golang interface into a TypeScript discriminated union.
+type UnionType struct {
+	Type          reflect.Type
+	Discriminator string
+	Variants      []UnionVariant
+}
+
+// UnionVariant is one struct implementing the union interface.
+type UnionVariant struct {
+	Value string
+	Type  reflect.Type
+}
+
+// AddUnion registers an interface type which will be converted into a union of its variants. Variants are
+// recognized by the (JSON) discriminator field, the map keys are the discriminator values:
+//
+//	converter.AddUnion((*Shape)(nil), "kind", map[string]interface{}{"circle": Circle{}, "square": Square{}})
+func (t *TypeScriptify) AddUnion(iface interface{}, discriminator string, variants map[string]interface{}) *TypeScriptify {
+	var ifaceType reflect.Type
+	if ty, is := iface.(reflect.Type); is {
+		ifaceType = ty
+	} else {
+		ifaceType = reflect.TypeOf(iface)
+	}
+	if ifaceType.Kind() == reflect.Ptr {
+		ifaceType = ifaceType.Elem()
+	}
+	if ifaceType.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("%s isn't an interface", ifaceType.String()))
+	}
+
+	values := make([]string, 0, len(variants))
+	for value := range variants {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	union := UnionType{Type: ifaceType, Discriminator: discriminator}
+	for _, value := range values {
+		var variantType reflect.Type
+		if ty, is := variants[value].(reflect.Type); is {
+			variantType = ty
+		} else {
+			variantType = reflect.TypeOf(variants[value])
+		}
+		if !variantType.Implements(ifaceType) && !reflect.PtrTo(variantType).Implements(ifaceType) {
+			panic(fmt.Sprintf("%s doesn't implement %s", variantType.String(), ifaceType.String()))
+		}
+		if variantType.Kind() == reflect.Ptr {
+			variantType = variantType.Elem()
+		}
+		union.Variants = append(union.Variants, UnionVariant{Value: value, Type: variantType})
+	}
+
+	if t.unions == nil {
+		t.unions = map[reflect.Type]UnionType{}
+	}
+	if _, found := t.unions[ifaceType]; !found {
+		t.unionTypes = append(t.unionTypes, ifaceType)
+	}
+	t.unions[ifaceType] = union
+	return t
+}
+
+// discriminatorValue returns the discriminator value if the field is the discriminator of an union variant.
+func (t *TypeScriptify) discriminatorValue(variantType reflect.Type, jsonFieldName string) (string, bool) {
+	for _, ifaceType := range t.unionTypes {
+		union := t.unions[ifaceType]
+		if union.Discriminator != jsonFieldName {
+			continue
+		}
+		for _, variant := range union.Variants {
+			if variant.Type == variantType {
+				return variant.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// unionField returns the union type used in a field (also in slices, arrays and maps of unions).
+func (t *TypeScriptify) unionField(typ reflect.Type) (union UnionType, arrayDepth int, asMap bool, found bool) {
+	for typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		typ = typ.Elem()
+		arrayDepth++
+	}
+	if typ.Kind() == reflect.Map && arrayDepth == 0 {
+		typ = typ.Elem()
+		asMap = true
+	}
+	union, found = t.unions[typ]
+	return
+}
+
+func (t *TypeScriptify) convertUnion(depth int, union UnionType, customCode map[string]string) (string, error) {
+	t.logf(depth, "Converting union %s", union.Type.String())
+	if _, found := t.alreadyConverted[union.Type]; found { // Already converted
+		return "", nil
+	}
+	t.alreadyConverted[union.Type] = true
+	t.beginDeclaration()
+
+	deps := ""
+	var variantNames []string
+	for _, variant := range union.Variants {
+		hasDiscriminator := false
+		for _, field := range deepFields(variant.Type) {
+			if strings.ReplaceAll(t.getJSONFieldName(field, false), "?", "") == union.Discriminator {
+				hasDiscriminator = true
+			}
+		}
+		if !hasDiscriminator {
+			return "", fmt.Errorf("union variant %s has no %s field", variant.Type.String(), union.Discriminator)
+		}
+
+		typeScriptChunk, err := t.convertType(depth+1, variant.Type, customCode)
+		if err != nil {
+			return "", err
+		}
+		if typeScriptChunk != "" {
+			deps += typeScriptChunk + "\n"
+		}
+		variantNames = append(variantNames, t.structName(variant.Type))
+	}
+
+	entityName := t.typeName(union.Type)
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+
+	if t.CreateZodSchema {
+		var variantSchemas []string
+		for _, variantName := range variantNames {
+			variantSchemas = append(variantSchemas, t.zodRef(variantName))
+		}
+		schemaName := zodSchemaName(entityName)
+		t.zodDeclared[schemaName] = true
+		result := fmt.Sprintf("%sconst %s = z.discriminatedUnion(%q, [%s]);\n", export, schemaName, union.Discriminator, strings.Join(variantSchemas, ", "))
+		result += fmt.Sprintf("%stype %s = z.infer<typeof %s>;", export, entityName, schemaName)
+		return deps + t.declare(union.Type, result), nil
+	}
+
+	for _, variantName := range variantNames {
+		t.reference(variantName)
+	}
+	result := fmt.Sprintf("%stype %s = %s;", export, entityName, strings.Join(variantNames, " | "))
+	if !t.CreateInterface && (t.CreateConstructor || t.CreateFromMethod) {
+		result += fmt.Sprintf("\n%sconst %s = {\n", export, entityName)
+		result += fmt.Sprintf("%sdiscriminator: %q,\n", t.Indent, union.Discriminator)
+		result += fmt.Sprintf("%svariants: {\n", t.Indent)
+		for n, variant := range union.Variants {
+			result += fmt.Sprintf("%s%s%q: %s,\n", t.Indent, t.Indent, variant.Value, variantNames[n])
+		}
+		result += fmt.Sprintf("%s} as {[key: string]: any},\n", t.Indent)
+		result += "};"
+	}
+	if t.CreateTypeGuards {
+		result += "\n" + t.unionGuard(union, entityName, variantNames)
+	}
+
+	return deps + t.declare(union.Type, result), nil
+}
@@ -0,0 +1,129 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Kind   string  `json:"kind"`
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+
+type Square struct {
+	Kind string  `json:"kind"`
+	Side float64 `json:"side"`
+}
+
+func (s Square) Area() float64 { return s.Side * s.Side }
+
+type Drawing struct {
+	Main   Shape            `json:"main"`
+	Shapes []Shape          `json:"shapes"`
+	Named  map[string]Shape `json:"named"`
+}
+
+func TestUnion(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"square": Square{}, "circle": Circle{}}).
+		Add(Drawing{}).
+		WithBackupDir("")
+
+	desiredResult := `export class Circle {
+    kind: "circle";
+    radius: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.kind = source["kind"];
+        this.radius = source["radius"];
+    }
+}
+export class Square {
+    kind: "square";
+    side: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.kind = source["kind"];
+        this.side = source["side"];
+    }
+}
+export type Shape = Circle | Square;
+export const Shape = {
+    discriminator: "kind",
+    variants: {
+        "circle": Circle,
+        "square": Square,
+    } as {[key: string]: any},
+};
+export class Drawing {
+    main: Shape;
+    shapes: Shape[];
+    named: {[key: string]: Shape};
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.main = this.convertValues(source["main"], Shape);
+        this.shapes = this.convertValues(source["shapes"], Shape);
+        this.named = this.convertValues(source["named"], Shape, true);
+    }
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := jsonizeOrPanic(Drawing{
+		Main:   Circle{Kind: "circle", Radius: 2},
+		Shapes: []Shape{Square{Kind: "square", Side: 1}, Circle{Kind: "circle", Radius: 1}},
+		Named:  map[string]Shape{"sq": Square{Kind: "square", Side: 3}},
+	})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Drawing(` + jsn + `).main instanceof Circle`,
+		`(new Drawing(` + jsn + `).main as Circle).radius === 2`,
+		`new Drawing(` + jsn + `).shapes[0] instanceof Square`,
+		`new Drawing(` + jsn + `).shapes[1] instanceof Circle`,
+		`new Drawing(` + jsn + `).named["sq"] instanceof Square`,
+		`(() => { const s = new Drawing(` + jsn + `).main; return s.kind === "circle" && s.radius === 2; })()`,
+	})
+}
+
+func TestUnionWithInterfaces(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"square": Square{}, "circle": &Circle{}}).
+		Add(Drawing{}).
+		WithInterface(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface Circle {
+    kind: "circle";
+    radius: number;
+}
+export interface Square {
+    kind: "square";
+    side: number;
+}
+export type Shape = Circle | Square;
+export interface Drawing {
+    main: Shape;
+    shapes: Shape[];
+    named: {[key: string]: Shape};
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestUnionWithoutDiscriminator(t *testing.T) {
+	t.Parallel()
+	_, err := New().
+		AddUnion((*Shape)(nil), "type", map[string]interface{}{"circle": Circle{}}).
+		WithBackupDir("").
+		Convert(nil)
+	assert.EqualError(t, err, "union variant typescriptify.Circle has no type field")
+}
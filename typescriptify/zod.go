@@ -0,0 +1,198 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const tsZodTag = "ts_zod"
+
+// WithZodSchema creates zod schemas (and types inferred from them) instead of classes or interfaces.
+func (t *TypeScriptify) WithZodSchema(b bool) *TypeScriptify {
+	t.CreateZodSchema = b
+	return t
+}
+
+func zodSchemaName(entityName string) string {
+	return entityName + "Schema"
+}
+
+// zodRef returns the reference to a schema, schemas not (yet) declared are referenced lazily.
+func (t *TypeScriptify) zodRef(entityName string) string {
+	schemaName := t.reference(zodSchemaName(entityName))
+	if t.zodDeclared[schemaName] {
+		return schemaName
+	}
+	t.zodLazy[schemaName] = true
+	return "z.lazy(() => " + schemaName + ")"
+}
+
+// zodSchema returns the zod schema for a type, types contains the type in every instantiation of the generic struct g
+// (or just one type if g is nil).
+func (t *TypeScriptify) zodSchema(g *genericStruct, types []reflect.Type) string {
+	typ := types[0]
+	hasParams := false
+	if g != nil {
+		if n := g.paramIndex(types); n >= 0 {
+			return g.params[n]
+		}
+		// A slice of custom marshalers in one instantiation can be a slice of structs in another:
+		_, hasParams = t.genericTypeExpr(g, types)
+	}
+	if t.isPrimitiveAlias(typ) {
+		return t.zodRef(t.typeName(typ))
+	}
+	if opts, found := t.typeOptions(typ); found && !hasParams {
+		if opts.TSZod != "" {
+			return opts.TSZod
+		}
+		if opts.TSType != "" {
+			return fmt.Sprintf("z.custom<%s>()", opts.TSType)
+		}
+	}
+	if _, zodSchema, is := t.marshalerType(typ); is && !hasParams {
+		return zodSchema
+	}
+	if _, isEnum := t.enums[typ]; isEnum {
+		return t.reference(zodSchemaName(t.typeName(typ)))
+	}
+	if union, isUnion := t.unions[typ]; isUnion {
+		return t.zodRef(t.typeName(union.Type))
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.zodSchema(g, mapTypes(types, reflect.Type.Elem))
+	case reflect.Slice, reflect.Array:
+		return "z.array(" + t.zodSchema(g, mapTypes(types, reflect.Type.Elem)) + ")"
+	case reflect.Map:
+		return "z.record(" + t.mapKeyZodSchema(typ.Key()) + ", " + t.zodSchema(g, mapTypes(types, reflect.Type.Elem)) + ")"
+	case reflect.Struct:
+		if t.isInlineStruct(typ) {
+			var fields []string
+			for _, fld := range t.structFields(typ) {
+				fields = append(fields, fmt.Sprintf("%s: %s", tsPropertyName(strings.ReplaceAll(fld.name, "?", "")), t.zodFieldSchema(nil, fld)))
+			}
+			return "z.object({ " + strings.Join(fields, ", ") + " })"
+		}
+		nested := t.genericOf(typ)
+		if nested == nil {
+			return t.zodRef(t.structName(typ))
+		}
+		var args []string
+		for n := range nested.params {
+			argTypes := make([]reflect.Type, len(types))
+			known := true
+			for i := range types {
+				argTypes[i] = t.argTypes(types[i])[n]
+				known = known && argTypes[i] != nil
+			}
+			if known {
+				args = append(args, t.zodSchema(g, argTypes))
+			} else {
+				args = append(args, "z.any()")
+			}
+		}
+		return t.reference(zodSchemaName(t.structName(typ))) + "(" + strings.Join(args, ", ") + ")"
+	}
+
+	return t.zodKindSchema(typ.Kind())
+}
+
+// zodKindSchema returns the schema for values of a basic kind.
+func (t *TypeScriptify) zodKindSchema(kind reflect.Kind) string {
+	switch t.kinds[kind] {
+	case "string":
+		return "z.string()"
+	case "number":
+		return "z.number()"
+	case "boolean":
+		return "z.boolean()"
+	case "":
+		return "z.any()"
+	default:
+		return fmt.Sprintf("z.custom<%s>()", t.kinds[kind])
+	}
+}
+
+// zodFieldSchema returns the schema for a struct field, including overrides from tags and custom types.
+func (t *TypeScriptify) zodFieldSchema(g *genericStruct, fld fieldInfo) string {
+	schema := ""
+	switch {
+	case len(fld.types) > 0 && t.isAliasField(fld.types[0], fld.opts):
+		schema = t.zodSchema(g, fld.types)
+	case fld.opts.TSZod != "":
+		schema = fld.opts.TSZod
+	case fld.opts.TSTransform != "":
+		tsType := ""
+		if fld.opts.TSType != "" {
+			tsType = ": " + fld.opts.TSType
+		}
+		schema = fmt.Sprintf("z.any().transform((v: any)%s => %s)", tsType, strings.Replace(fld.opts.TSTransform, "__VALUE__", "v", -1))
+	case fld.opts.TSType != "":
+		schema = fmt.Sprintf("z.custom<%s>()", fld.opts.TSType)
+	default:
+		schema = t.zodSchema(g, fld.types)
+	}
+	if fld.nullable {
+		schema += ".nullable()"
+	}
+	if strings.HasSuffix(fld.name, "?") {
+		schema += ".optional()"
+	}
+	return schema
+}
+
+// zodDeclaration returns the schema (and type) declarations for a converted struct.
+func (t *TypeScriptify) zodDeclaration(entityName, header string, generic *genericStruct, bases []reflect.Type, builder typeScriptClassBuilder, fields []fieldInfo, catchall string) string {
+	export := ""
+	if !t.DontExport {
+		export = "export "
+	}
+	schemaName := zodSchemaName(entityName)
+	// Names used by the interface are only referenced if it's declared:
+	ifaceReferences := t.endDeclaration()
+	t.beginDeclaration()
+
+	object := "z.object({\n"
+	if len(bases) > 0 {
+		object = t.zodSchema(nil, bases[0:1])
+		for _, base := range bases[1:] {
+			object += ".merge(" + t.zodSchema(nil, []reflect.Type{base}) + ")"
+		}
+		object += ".extend({\n"
+	}
+	for _, fld := range fields {
+		if fld.doc != "" {
+			object += t.Indent + "/** " + fld.doc + " */\n"
+		}
+		object += fmt.Sprintf("%s%s: %s,\n", t.Indent, tsPropertyName(strings.ReplaceAll(fld.name, "?", "")), t.zodFieldSchema(generic, fld))
+	}
+	object += "})"
+	if catchall != "" {
+		object += ".catchall(" + catchall + ")"
+	}
+
+	t.zodDeclared[schemaName] = true
+
+	iface := export + "interface " + header + " {\n" + strings.Join(builder.fields, "\n") + "\n}\n"
+	if generic != nil || t.zodLazy[schemaName] {
+		for name := range ifaceReferences {
+			t.reference(name)
+		}
+	}
+	if generic != nil {
+		var params []string
+		for _, param := range generic.params {
+			params = append(params, fmt.Sprintf("%s: %s", param, param))
+		}
+		typeParams := strings.Join(generic.params, " extends z.ZodTypeAny, ") + " extends z.ZodTypeAny"
+		return iface + fmt.Sprintf("%sconst %s = <%s>(%s) => %s;", export, schemaName, typeParams, strings.Join(params, ", "), object)
+	}
+	if t.zodLazy[schemaName] {
+		// Recursive schemas need an explicit type:
+		return iface + fmt.Sprintf("%sconst %s: z.ZodType<%s> = %s;", export, schemaName, entityName, object)
+	}
+	return fmt.Sprintf("%sconst %s = %s;\n%stype %s = z.infer<typeof %s>;", export, schemaName, object, export, entityName, schemaName)
+}
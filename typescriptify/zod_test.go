@@ -0,0 +1,168 @@
+package typescriptify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestZodSchema(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Person{}).
+		WithZodSchema(true).
+		WithBackupDir("")
+
+	desiredResult := `import { z } from "zod";
+
+export const DummySchema = z.object({
+    something: z.string(),
+});
+export type Dummy = z.infer<typeof DummySchema>;
+export const AddressSchema = z.object({
+    duration: z.number(),
+    text: z.string().optional(),
+    Text2: z.string().optional(),
+});
+export type Address = z.infer<typeof AddressSchema>;
+export interface Person {
+    name: string;
+    nicknames: string[];
+    addresses: Address[];
+    address?: Address;
+    metadata: {[key:string]:string};
+    friends: Person[];
+    a: Dummy;
+}
+export const PersonSchema: z.ZodType<Person> = z.object({
+    name: z.string(),
+    nicknames: z.array(z.string()),
+    addresses: z.array(AddressSchema),
+    address: AddressSchema.optional(),
+    metadata: z.any().transform((v: any): {[key:string]:string} => JSON.parse(v || "{}")),
+    friends: z.array(z.lazy(() => PersonSchema)),
+    a: DummySchema,
+});`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestZodSchemaWithEnumsAndOverrides(t *testing.T) {
+	t.Parallel()
+	type Event struct {
+		Weekday Weekday        `json:"weekday" ts_doc:"Day of the event"`
+		Time    time.Time      `json:"time"`
+		Counts  map[string]int `json:"counts"`
+		Code    string         `json:"code" ts_zod:"z.string().length(3)"`
+	}
+
+	converter := New().
+		AddEnum(allWeekdaysV2).
+		Add(Event{}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)", TSZod: "z.coerce.date()"}).
+		WithZodSchema(true).
+		WithBackupDir("")
+
+	desiredResult := `import { z } from "zod";
+
+export enum Weekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export const WeekdaySchema = z.nativeEnum(Weekday);
+export const EventSchema = z.object({
+    /** Day of the event */
+    weekday: WeekdaySchema,
+    time: z.coerce.date(),
+    counts: z.record(z.string(), z.number()),
+    code: z.string().length(3),
+});
+export type Event = z.infer<typeof EventSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestZodSchemaWithGenericsAndUnions(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddUnion((*Shape)(nil), "kind", map[string]interface{}{"square": Square{}, "circle": Circle{}}).
+		Add(Page[HasName]{}).
+		Add(Page[Drawing]{}).
+		WithZodSchema(true).
+		WithBackupDir("")
+
+	desiredResult := `import { z } from "zod";
+
+export const CircleSchema = z.object({
+    kind: z.literal("circle"),
+    radius: z.number(),
+});
+export type Circle = z.infer<typeof CircleSchema>;
+export const SquareSchema = z.object({
+    kind: z.literal("square"),
+    side: z.number(),
+});
+export type Square = z.infer<typeof SquareSchema>;
+export const ShapeSchema = z.discriminatedUnion("kind", [CircleSchema, SquareSchema]);
+export type Shape = z.infer<typeof ShapeSchema>;
+export const HasNameSchema = z.object({
+    name: z.string(),
+});
+export type HasName = z.infer<typeof HasNameSchema>;
+export interface Page<T> {
+    items: T[];
+    total: number;
+}
+export const PageSchema = <T extends z.ZodTypeAny>(T: T) => z.object({
+    items: z.array(T),
+    total: z.number(),
+});
+export const DrawingSchema = z.object({
+    main: ShapeSchema,
+    shapes: z.array(ShapeSchema),
+    named: z.record(z.string(), ShapeSchema),
+});
+export type Drawing = z.infer<typeof DrawingSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+}
+
+func TestZodSchemaWithNestedContainers(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		WithPrefix("API").
+		AddEnum(allWeekdaysV1).
+		Add(Nested{}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)", TSZod: "z.coerce.date()"}).
+		WithZodSchema(true).
+		WithBackupDir("")
+
+	desiredResult := `import { z } from "zod";
+
+export enum APIWeekday {
+    SUNDAY = 0,
+    MONDAY = 1,
+    TUESDAY = 2,
+    WEDNESDAY = 3,
+    THURSDAY = 4,
+    FRIDAY = 5,
+    SATURDAY = 6,
+}
+export const APIWeekdaySchema = z.nativeEnum(APIWeekday);
+export const APIItemSchema = z.object({
+    name: z.string(),
+});
+export type APIItem = z.infer<typeof APIItemSchema>;
+export const APINestedSchema = z.object({
+    grouped: z.record(z.string(), z.array(APIItemSchema)),
+    matrix: z.record(z.string(), z.record(z.string(), z.number())),
+    deep: z.record(z.string(), z.record(z.string(), APIItemSchema)),
+    rows: z.array(z.record(z.string(), APIItemSchema)),
+    grid: z.array(z.array(APIItemSchema)),
+    days: z.array(APIWeekdaySchema),
+    times: z.record(z.string(), z.array(z.coerce.date())),
+});
+export type APINested = z.infer<typeof APINestedSchema>;`
+	testConvertedCode(t, converter, desiredResult)
+}